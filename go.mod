@@ -18,4 +18,5 @@ require (
 	google.golang.org/appengine v1.6.0 // indirect
 	google.golang.org/genproto v0.0.0-20190530194941-fb225487d101
 	google.golang.org/grpc v1.21.0 // indirect
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -2,14 +2,25 @@ package main
 
 import (
 	"flag"
+	"os"
 
+	"github.com/UnityTech/nemesis/pkg/config"
 	"github.com/UnityTech/nemesis/pkg/runner"
+	"github.com/golang/glog"
 )
 
 func main() {
-	flag.Parse()
-	audit := runner.NewAudit()
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		glog.Exitf("Failed to load configuration: %v", err)
+	}
+
+	audit := runner.NewAudit(cfg)
 	audit.Setup()
 	audit.Execute()
 	audit.Report()
+
+	if err := audit.CheckThreshold(); err != nil {
+		glog.Exitf("Audit failed: %v", err)
+	}
 }
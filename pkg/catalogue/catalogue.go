@@ -0,0 +1,94 @@
+// Package catalogue describes every control nemesis evaluates, with its severity and how to remediate it
+package catalogue
+
+import (
+	"strings"
+)
+
+const (
+	// Low severity findings are hardening opportunities
+	Low = "low"
+
+	// Medium severity findings weaken defence in depth or reduce visibility
+	Medium = "medium"
+
+	// High severity findings grant excessive access or expose services
+	High = "high"
+
+	// Critical severity findings expose data or infrastructure to the internet
+	Critical = "critical"
+
+	// cisBenchmark is the reference for every CIS recommendation
+	cisBenchmark = "https://www.cisecurity.org/benchmark/google_cloud_computing_platform/"
+)
+
+// Entry is the catalogue entry of a control
+type Entry struct {
+	// ID is a stable identifier for the control, which does not change with its title
+	ID string `json:"id"`
+
+	// Severity is one of low, medium, high or critical
+	Severity string `json:"severity"`
+
+	// Rationale explains why the control matters
+	Rationale string `json:"rationale,omitempty"`
+
+	// Remediation describes the steps to fix a failing resource
+	Remediation string `json:"remediation,omitempty"`
+
+	// Gcloud is a gcloud command that remediates a failing resource. The {project} and {resource} placeholders
+	// are replaced with the project ID and name of the resource, other placeholders must be filled in by hand.
+	// {resource} must only be used when every report the control is evaluated on is about that kind of resource
+	Gcloud string `json:"gcloud,omitempty"`
+
+	// References are URLs documenting the control
+	References []string `json:"references,omitempty"`
+}
+
+var (
+	// Severities lists the severities from least to most severe
+	Severities = []string{Low, Medium, High, Critical}
+
+	// registry holds catalogue entries by CIS ID for CIS controls, and by control title, up to the first '=', for other controls
+	registry = make(map[string]Entry, 1)
+)
+
+// Lookup returns the catalogue entry of a CIS ID, or of a control title that is not a CIS control.
+// Titles with parameters, such as numNetworkInterfaces=1, are looked up by the part before the '='
+func Lookup(key string) (Entry, bool) {
+	if i := strings.Index(key, "="); i >= 0 {
+		key = key[:i]
+	}
+	e, ok := registry[key]
+	return e, ok
+}
+
+// Rank returns the position of a severity in Severities, starting at 1 for low, or 0 for an unknown severity
+func Rank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Command returns the gcloud remediation command for a resource, or an empty string if there is none. Commands that
+// need a project are not returned for resources outside of a project, such as organization and folder IAM policies
+func (e *Entry) Command(projectID string, resource string) string {
+	if e.Gcloud == "" {
+		return ""
+	}
+	if projectID == "" && strings.Contains(e.Gcloud, "{project}") {
+		return ""
+	}
+	r := strings.NewReplacer("{project}", projectID, "{resource}", resource)
+	return r.Replace(e.Gcloud)
+}
+
+func register(key string, e Entry) {
+	if _, ok := registry[key]; ok {
+		panic("duplicate catalogue entry " + key)
+	}
+	registry[key] = e
+}
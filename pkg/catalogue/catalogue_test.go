@@ -0,0 +1,53 @@
+package catalogue
+
+import (
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/cis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+
+	// Every CIS recommendation has a catalogue entry
+	for id := range cis.Registry {
+		_, ok := Lookup(id)
+		assert.True(t, ok, id)
+	}
+
+	ids := map[string]string{}
+	for key, e := range registry {
+		assert.Contains(t, []string{Low, Medium, High, Critical}, e.Severity, key)
+		assert.NotEmpty(t, e.Rationale, key)
+		assert.NotEmpty(t, e.Remediation, key)
+		assert.NotEmpty(t, e.References, key)
+
+		// IDs are unique
+		other, ok := ids[e.ID]
+		assert.False(t, ok, "%v is used by both %v and %v", e.ID, key, other)
+		ids[e.ID] = key
+	}
+}
+
+func TestLookup(t *testing.T) {
+
+	e, ok := Lookup("numNetworkInterfaces=2")
+	assert.True(t, ok)
+	assert.Equal(t, "compute-network-interfaces", e.ID)
+
+	_, ok = Lookup("unknown")
+	assert.False(t, ok)
+
+	e, _ = Lookup("5.1")
+	assert.Equal(t, "gsutil iam ch -d allUsers -d allAuthenticatedUsers gs://my-bucket", e.Command("my-project", "my-bucket"))
+
+	// Project commands are not returned for organization and folder policies
+	e, _ = Lookup("1.5")
+	assert.Equal(t, "", e.Command("", "organizations/123"))
+}
+
+func TestRank(t *testing.T) {
+	assert.True(t, Rank(Critical) > Rank(High))
+	assert.True(t, Rank(Medium) > Rank(Low))
+	assert.Equal(t, 0, Rank("severe"))
+}
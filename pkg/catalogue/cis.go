@@ -0,0 +1,349 @@
+package catalogue
+
+// registerCIS registers the catalogue entry of a CIS recommendation. Every entry references the CIS benchmark
+func registerCIS(cisID string, severity string, rationale string, remediation string, gcloud string, references ...string) {
+	register(cisID, Entry{
+		ID:          "cis-" + cisID,
+		Severity:    severity,
+		Rationale:   rationale,
+		Remediation: remediation,
+		Gcloud:      gcloud,
+		References:  append([]string{cisBenchmark}, references...),
+	})
+}
+
+func init() {
+	// IAM
+	registerCIS("1.1", High,
+		"Personal Gmail accounts are outside of the organization's control, so access cannot be revoked when people leave and their credentials are not subject to corporate policies.",
+		"Remove bindings for gmail.com members from the IAM policy and grant the same roles to corporate identities instead.",
+		"gcloud projects remove-iam-policy-binding {project} --member=user:<GMAIL_ADDRESS> --role=<ROLE>",
+		"https://cloud.google.com/iam/docs/overview")
+	registerCIS("1.2", High,
+		"Multi-factor authentication protects accounts when passwords are phished or reused.",
+		"Enforce 2-step verification for every user of the organization's Cloud Identity or G Suite domain.",
+		"",
+		"https://support.google.com/a/answer/175197")
+	registerCIS("1.3", High,
+		"User-managed service account keys are long-lived credentials that are easily leaked, while GCP-managed keys are rotated automatically.",
+		"Delete user-managed keys and use GCP-managed keys, workload identity or the metadata server instead. Enforce the iam.disableServiceAccountKeyCreation organization policy.",
+		"gcloud iam service-accounts keys delete <KEY_ID> --iam-account=<SERVICE_ACCOUNT> --project={project}",
+		"https://cloud.google.com/iam/docs/creating-managing-service-account-keys")
+	registerCIS("1.4", High,
+		"Service accounts with admin, owner or editor roles grant far more access than applications need, so a leaked key compromises the whole project.",
+		"Replace admin, owner and editor roles granted to service accounts with the least privileged predefined or custom roles.",
+		"gcloud projects remove-iam-policy-binding {project} --member=serviceAccount:<SERVICE_ACCOUNT> --role=<ROLE>",
+		"https://cloud.google.com/iam/docs/understanding-service-accounts")
+	registerCIS("1.5", High,
+		"The Service Account User role granted at the project level lets users act as every service account in the project, including privileged ones.",
+		"Remove project level bindings of roles/iam.serviceAccountUser and grant the role on individual service accounts instead.",
+		"gcloud projects remove-iam-policy-binding {project} --member=<MEMBER> --role=roles/iam.serviceAccountUser",
+		"https://cloud.google.com/iam/docs/service-accounts")
+	registerCIS("1.6", Medium,
+		"Rotating service account keys limits the window in which a leaked key can be used.",
+		"Create a new key for the service account, deploy it, then delete keys older than the allowed age.",
+		"gcloud iam service-accounts keys create <KEY_FILE> --iam-account=<SERVICE_ACCOUNT> --project={project}",
+		"https://cloud.google.com/iam/docs/creating-managing-service-account-keys")
+	registerCIS("1.7", Medium,
+		"Users holding both the Service Account Admin and Service Account User roles can create service accounts and act as them, bypassing separation of duties.",
+		"Grant roles/iam.serviceAccountAdmin and roles/iam.serviceAccountUser to different members.",
+		"gcloud projects remove-iam-policy-binding {project} --member=<MEMBER> --role=roles/iam.serviceAccountUser",
+		"https://cloud.google.com/iam/docs/service-accounts")
+	registerCIS("1.8", Medium,
+		"Rotating encryption keys limits the amount of data protected by a single key version if it is compromised.",
+		"Set a rotation period of 365 days or less on the crypto key.",
+		"gcloud kms keys update {resource} --keyring=<KEY_RING> --location=<LOCATION> --project={project} --rotation-period=90d --next-rotation-time=<NEXT_ROTATION_TIME>",
+		"https://cloud.google.com/kms/docs/key-rotation")
+	registerCIS("1.9", Medium,
+		"Users holding both the Cloud KMS Admin role and a role that uses keys can manage and use keys on their own, bypassing separation of duties.",
+		"Grant roles/cloudkms.admin and the CryptoKey Encrypter/Decrypter roles to different members.",
+		"gcloud projects remove-iam-policy-binding {project} --member=<MEMBER> --role=roles/cloudkms.admin",
+		"https://cloud.google.com/kms/docs/separation-of-duties")
+	registerCIS("1.10", Low,
+		"API keys are simple encrypted strings that identify a project rather than a user, and are easily leaked in client code.",
+		"Delete API keys and use service accounts or OAuth clients instead.",
+		"",
+		"https://cloud.google.com/docs/authentication/api-keys")
+	registerCIS("1.11", Medium,
+		"Unrestricted API keys can be used from any host or application if they leak.",
+		"Restrict the API key to the HTTP referrers, IP addresses or Android and iOS applications that use it.",
+		"",
+		"https://cloud.google.com/docs/authentication/api-keys")
+	registerCIS("1.12", Medium,
+		"API keys that are not restricted to specific APIs can call every API enabled in the project.",
+		"Restrict the API key to the APIs that the application needs.",
+		"",
+		"https://cloud.google.com/docs/authentication/api-keys")
+	registerCIS("1.13", Medium,
+		"Rotating API keys limits the window in which a leaked key can be used.",
+		"Regenerate the API key, deploy the new key and delete the old one.",
+		"",
+		"https://cloud.google.com/docs/authentication/api-keys")
+
+	// Logging and monitoring
+	registerCIS("2.1", Medium,
+		"Data access audit logs for all services and users are needed to investigate who read or modified data.",
+		"Enable ADMIN_READ, DATA_READ and DATA_WRITE audit logs for allServices in the IAM policy, without exempted members.",
+		"",
+		"https://cloud.google.com/logging/docs/audit/configure-data-access")
+	registerCIS("2.2", Medium,
+		"Without a sink exporting all log entries, logs are only kept for the default retention period.",
+		"Create a sink without a filter that exports all log entries to a storage bucket, BigQuery dataset or Pub/Sub topic.",
+		"gcloud logging sinks create <SINK_NAME> <DESTINATION> --project={project}",
+		"https://cloud.google.com/logging/docs/export")
+	registerCIS("2.3", Medium,
+		"Object versioning prevents exported logs from being overwritten or deleted.",
+		"Enable object versioning on buckets that logging sinks export to.",
+		"gsutil versioning set on gs://<BUCKET>",
+		"https://cloud.google.com/storage/docs/object-versioning")
+	registerCIS("2.4", Medium,
+		"Alerting on project ownership changes detects privilege escalation.",
+		"Create a log metric for SetIamPolicy calls that change roles/owner, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.5", Medium,
+		"Alerting on audit configuration changes detects attempts to disable audit logging.",
+		"Create a log metric for SetIamPolicy calls that change audit configs, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.6", Low,
+		"Alerting on custom role changes detects privileges being added to roles.",
+		"Create a log metric for iam_role create, delete and update activity, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.7", Medium,
+		"Alerting on firewall rule changes detects services being exposed.",
+		"Create a log metric for gce_firewall_rule insert, patch and delete operations, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.8", Low,
+		"Alerting on route changes detects traffic being diverted.",
+		"Create a log metric for gce_route insert and delete operations, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.9", Low,
+		"Alerting on network changes detects unexpected networks or peerings.",
+		"Create a log metric for gce_network insert, patch, delete and peering operations, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.10", Medium,
+		"Alerting on bucket permission changes detects data being made public.",
+		"Create a log metric for storage.setIamPermissions on gcs_bucket resources, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+	registerCIS("2.11", Low,
+		"Alerting on Cloud SQL configuration changes detects weakened database settings.",
+		"Create a log metric for cloudsql.instances.update, and an alerting policy on the metric.",
+		"",
+		"https://cloud.google.com/logging/docs/logs-based-metrics")
+
+	// Networking
+	registerCIS("3.1", Medium,
+		"The default network has permissive firewall rules and automatically created subnetworks that are rarely needed.",
+		"Create a custom network for the project's workloads, then delete the default network.",
+		"gcloud compute networks delete default --project={project}",
+		"https://cloud.google.com/vpc/docs/vpc")
+	registerCIS("3.2", Medium,
+		"Legacy networks have a single IP range for all regions and do not support subnetworks, flow logs or Private Google Access.",
+		"Migrate workloads to a VPC network, then delete the legacy network.",
+		"gcloud compute networks delete {resource} --project={project}",
+		"https://cloud.google.com/vpc/docs/legacy")
+	registerCIS("3.3", Medium,
+		"DNSSEC protects clients from spoofed DNS responses.",
+		"Enable DNSSEC on the public managed zone.",
+		"gcloud dns managed-zones update {resource} --project={project} --dnssec-state=on",
+		"https://cloud.google.com/dns/docs/dnssec-config")
+	registerCIS("3.4", Medium,
+		"RSASHA1 is a weak algorithm for DNSSEC key-signing keys.",
+		"Turn DNSSEC off, then turn it back on with a stronger key-signing algorithm such as RSASHA256.",
+		"gcloud dns managed-zones update {resource} --project={project} --dnssec-state=on --ksk-algorithm=rsasha256 --ksk-key-length=2048 --zsk-algorithm=rsasha256 --zsk-key-length=1024",
+		"https://cloud.google.com/dns/docs/dnssec-config")
+	registerCIS("3.5", Medium,
+		"RSASHA1 is a weak algorithm for DNSSEC zone-signing keys.",
+		"Turn DNSSEC off, then turn it back on with a stronger zone-signing algorithm such as RSASHA256.",
+		"gcloud dns managed-zones update {resource} --project={project} --dnssec-state=on --ksk-algorithm=rsasha256 --ksk-key-length=2048 --zsk-algorithm=rsasha256 --zsk-key-length=1024",
+		"https://cloud.google.com/dns/docs/dnssec-config")
+	registerCIS("3.6", High,
+		"SSH reachable from the internet exposes instances to brute force attacks and exploits.",
+		"Restrict the source ranges of the firewall rule to known addresses, or use Identity-Aware Proxy for SSH.",
+		"gcloud compute firewall-rules update {resource} --project={project} --source-ranges=<ALLOWED_RANGES>",
+		"https://cloud.google.com/vpc/docs/firewalls")
+	registerCIS("3.7", High,
+		"RDP reachable from the internet exposes instances to brute force attacks and exploits.",
+		"Restrict the source ranges of the firewall rule to known addresses, or use Identity-Aware Proxy for RDP.",
+		"gcloud compute firewall-rules update {resource} --project={project} --source-ranges=<ALLOWED_RANGES>",
+		"https://cloud.google.com/vpc/docs/firewalls")
+	registerCIS("3.8", Low,
+		"Private Google Access lets instances without external IP addresses reach Google APIs.",
+		"Enable Private Google Access on the subnetwork.",
+		"gcloud compute networks subnets update {resource} --project={project} --region=<REGION> --enable-private-ip-google-access",
+		"https://cloud.google.com/vpc/docs/configure-private-google-access")
+	registerCIS("3.9", Medium,
+		"VPC flow logs record network flows for monitoring, forensics and security analysis.",
+		"Enable flow logs on the subnetwork.",
+		"gcloud compute networks subnets update {resource} --project={project} --region=<REGION> --enable-flow-logs",
+		"https://cloud.google.com/vpc/docs/using-flow-logs")
+
+	// Compute
+	registerCIS("4.1", High,
+		"The default compute service account has the Editor role, so instances using it with full API access can modify the whole project.",
+		"Stop the instance and set a dedicated service account, or limit the access scopes of the default service account.",
+		"gcloud compute instances set-service-account {resource} --project={project} --zone=<ZONE> --service-account=<SERVICE_ACCOUNT> --scopes=<SCOPES>",
+		"https://cloud.google.com/compute/docs/access/service-accounts")
+	registerCIS("4.2", Medium,
+		"Project-wide SSH keys grant access to every instance in the project, so a single leaked key compromises all of them.",
+		"Block project-wide SSH keys in the project metadata, or in the metadata of every instance.",
+		"gcloud compute project-info add-metadata --project={project} --metadata=block-project-ssh-keys=TRUE",
+		"https://cloud.google.com/compute/docs/instances/adding-removing-ssh-keys")
+	registerCIS("4.3", Medium,
+		"OS Login ties SSH access to IAM identities, so access is revoked with the identity and keys are not managed by hand.",
+		"Enable OS Login in the project metadata, or enforce the compute.requireOsLogin organization policy.",
+		"gcloud compute project-info add-metadata --project={project} --metadata=enable-oslogin=TRUE",
+		"https://cloud.google.com/compute/docs/instances/managing-instance-access")
+	registerCIS("4.4", Medium,
+		"The interactive serial console does not support IP based access restrictions, so anyone with the right credentials can connect from anywhere.",
+		"Disable serial port access in the project metadata, and remove serial-port-enable from the metadata of every instance.",
+		"gcloud compute project-info add-metadata --project={project} --metadata=serial-port-enable=FALSE",
+		"https://cloud.google.com/compute/docs/instances/interacting-with-serial-console")
+	registerCIS("4.5", Medium,
+		"Instances that forward IP packets can route traffic that bypasses network controls.",
+		"Recreate the instance without IP forwarding, which cannot be changed on an existing instance.",
+		"",
+		"https://cloud.google.com/vpc/docs/using-routes")
+	registerCIS("4.6", Low,
+		"Customer-supplied encryption keys keep the keys protecting critical disks under the organization's control.",
+		"Recreate the disk from a snapshot, encrypted with a customer-supplied encryption key.",
+		"",
+		"https://cloud.google.com/compute/docs/disks/customer-supplied-encryption")
+
+	// Storage
+	registerCIS("5.1", Critical,
+		"Buckets readable by allUsers or allAuthenticatedUsers expose their data to the internet.",
+		"Remove allUsers and allAuthenticatedUsers from the bucket's IAM policy and ACLs.",
+		"gsutil iam ch -d allUsers -d allAuthenticatedUsers gs://{resource}",
+		"https://cloud.google.com/storage/docs/access-control/iam")
+	registerCIS("5.2", Critical,
+		"Objects readable by allUsers or allAuthenticatedUsers expose their data to the internet.",
+		"Remove allUsers and allAuthenticatedUsers from the object ACLs and the bucket's default object ACL, or enable uniform bucket-level access.",
+		"gsutil defacl ch -d allUsers -d allAuthenticatedUsers gs://{resource}",
+		"https://cloud.google.com/storage/docs/access-control/lists")
+	registerCIS("5.3", Low,
+		"Access logs record requests made to the bucket, for security investigations and usage analysis.",
+		"Enable access logging on the bucket, writing to a dedicated log bucket.",
+		"gsutil logging set on -b gs://<LOG_BUCKET> gs://{resource}",
+		"https://cloud.google.com/storage/docs/access-logs")
+
+	// Cloud SQL
+	registerCIS("6.1", High,
+		"Connections without SSL expose credentials and data in transit.",
+		"Require SSL for all connections to the instance.",
+		"gcloud sql instances patch {resource} --project={project} --require-ssl",
+		"https://cloud.google.com/sql/docs/mysql/configure-ssl-instance")
+	registerCIS("6.2", Critical,
+		"Instances that authorize 0.0.0.0/0 accept connections from the whole internet.",
+		"Replace the 0.0.0.0/0 authorized network with the addresses of known clients, or use private IP.",
+		"gcloud sql instances patch {resource} --project={project} --authorized-networks=<ALLOWED_RANGES>",
+		"https://cloud.google.com/sql/docs/mysql/configure-ip")
+	registerCIS("6.3", High,
+		"Administrative users that can connect from any host allow attackers to take over the database with a single password.",
+		"Restrict the host of administrative users, and set a strong password for them.",
+		"",
+		"https://cloud.google.com/sql/docs/mysql/create-manage-users")
+	registerCIS("6.4", High,
+		"A root user that can connect from any host allows attackers to take over the database with a single password.",
+		"Restrict the host of the root user.",
+		"",
+		"https://cloud.google.com/sql/docs/mysql/create-manage-users")
+
+	// Kubernetes Engine
+	registerCIS("7.1", Medium,
+		"Cluster logs are needed to investigate incidents and troubleshoot workloads.",
+		"Enable Stackdriver Logging on the cluster.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --logging-service=logging.googleapis.com",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/logging")
+	registerCIS("7.2", Low,
+		"Cluster metrics are needed to detect abnormal behaviour.",
+		"Enable Stackdriver Monitoring on the cluster.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --monitoring-service=monitoring.googleapis.com",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/monitoring")
+	registerCIS("7.3", High,
+		"Legacy ABAC authorization grants broad permissions that cannot be restricted with RBAC.",
+		"Disable legacy authorization on the cluster.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --no-enable-legacy-authorization",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/hardening-your-cluster")
+	registerCIS("7.4", High,
+		"Without master authorized networks, the Kubernetes API is reachable from any address.",
+		"Enable master authorized networks with the ranges that need to reach the Kubernetes API.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --enable-master-authorized-networks --master-authorized-networks=<ALLOWED_RANGES>",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/authorized-networks")
+	registerCIS("7.5", Low,
+		"Labels let clusters be attributed to teams and environments for billing and access reviews.",
+		"Add resource labels to the cluster.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --update-labels=<LABELS>",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/creating-managing-labels")
+	registerCIS("7.6", High,
+		"The Kubernetes dashboard runs with a privileged service account and has been abused to take over clusters.",
+		"Disable the Kubernetes dashboard add-on.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --update-addons=KubernetesDashboard=DISABLED",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/hardening-your-cluster")
+	registerCIS("7.7", Low,
+		"Automatic node repair replaces unhealthy nodes.",
+		"Enable automatic repair on the node pool.",
+		"gcloud container node-pools update {resource} --project={project} --cluster=<CLUSTER> --zone=<ZONE> --enable-autorepair",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/node-auto-repair")
+	registerCIS("7.8", Medium,
+		"Automatic node upgrades keep nodes patched against known vulnerabilities.",
+		"Enable automatic upgrades on the node pool.",
+		"gcloud container node-pools update {resource} --project={project} --cluster=<CLUSTER> --zone=<ZONE> --enable-autoupgrade",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/node-auto-upgrades")
+	registerCIS("7.9", Low,
+		"Container-Optimized OS has a minimal, hardened footprint that is maintained by Google.",
+		"Upgrade the node pool to the COS image type.",
+		"gcloud container clusters upgrade <CLUSTER> --project={project} --zone=<ZONE> --node-pool={resource} --image-type=COS",
+		"https://cloud.google.com/kubernetes-engine/docs/concepts/node-images")
+	registerCIS("7.10", High,
+		"Basic authentication uses a static password to access the Kubernetes API, which cannot be rotated without downtime.",
+		"Disable basic authentication by clearing the master username.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --no-enable-basic-auth",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/hardening-your-cluster")
+	registerCIS("7.11", Medium,
+		"Network policies restrict traffic between pods, limiting lateral movement.",
+		"Enable the network policy add-on and enforcement on the cluster.",
+		"gcloud container clusters update {resource} --project={project} --zone=<ZONE> --enable-network-policy",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/network-policy")
+	registerCIS("7.12", Medium,
+		"Client certificates are needed for some Kubernetes authentication flows and are issued at cluster creation.",
+		"Recreate the cluster with client certificates enabled, which cannot be changed on an existing cluster.",
+		"",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/hardening-your-cluster")
+	registerCIS("7.13", Low,
+		"Alias IP ranges make pod addresses routable and subject to firewall rules, and are required for private clusters.",
+		"Recreate the cluster as a VPC-native cluster with alias IP ranges.",
+		"",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/alias-ips")
+	registerCIS("7.14", Medium,
+		"The PodSecurityPolicy controller prevents privileged pods from being scheduled.",
+		"Enable the PodSecurityPolicy controller on the cluster, after defining policies for existing workloads.",
+		"gcloud beta container clusters update {resource} --project={project} --zone=<ZONE> --enable-pod-security-policy",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/pod-security-policies")
+	registerCIS("7.15", High,
+		"Nodes of private clusters have no external IP addresses, so they cannot be reached from the internet.",
+		"Recreate the cluster as a private cluster, which cannot be changed on an existing cluster.",
+		"",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/private-clusters")
+	registerCIS("7.16", Low,
+		"Private Google Access lets nodes without external IP addresses reach Google APIs.",
+		"Enable Private Google Access on the cluster's subnetwork.",
+		"gcloud compute networks subnets update <SUBNETWORK> --project={project} --region=<REGION> --enable-private-ip-google-access",
+		"https://cloud.google.com/vpc/docs/configure-private-google-access")
+	registerCIS("7.17", High,
+		"The default compute service account has the Editor role, so a compromised node can modify the whole project.",
+		"Recreate the node pool with a dedicated, least privileged service account.",
+		"gcloud container node-pools create <NODE_POOL> --project={project} --cluster=<CLUSTER> --zone=<ZONE> --service-account=<SERVICE_ACCOUNT>",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/hardening-your-cluster")
+	registerCIS("7.18", High,
+		"Broad access scopes let a compromised node call every Google API that its service account can access.",
+		"Recreate the node pool with the minimal access scopes.",
+		"gcloud container node-pools create <NODE_POOL> --project={project} --cluster=<CLUSTER> --zone=<ZONE> --scopes=<SCOPES>",
+		"https://cloud.google.com/kubernetes-engine/docs/how-to/hardening-your-cluster")
+}
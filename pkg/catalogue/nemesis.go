@@ -0,0 +1,112 @@
+package catalogue
+
+func init() {
+	// Compute
+	register("Ensure legacy metadata endpoints are not enabled for VM Instance", Entry{
+		ID:          "compute-legacy-metadata-endpoints",
+		Severity:    Medium,
+		Rationale:   "Legacy metadata endpoints do not require the Metadata-Flavor header, so server-side request forgery can read instance credentials.",
+		Remediation: "Set the disable-legacy-endpoints metadata key to true on the project.",
+		Gcloud:      "gcloud compute project-info add-metadata --project={project} --metadata=disable-legacy-endpoints=TRUE",
+		References:  []string{"https://cloud.google.com/compute/docs/storing-retrieving-metadata"},
+	})
+	register("numNetworkInterfaces", Entry{
+		ID:          "compute-network-interfaces",
+		Severity:    Low,
+		Rationale:   "Instances with several network interfaces can bridge networks that are meant to be isolated.",
+		Remediation: "Recreate the instance with the expected number of network interfaces.",
+		References:  []string{"https://cloud.google.com/vpc/docs/multiple-interfaces-concepts"},
+	})
+	register("hasNatIP", Entry{
+		ID:          "compute-external-ip",
+		Severity:    Medium,
+		Rationale:   "Instances with an external IP address can be reached from the internet if a firewall rule allows it.",
+		Remediation: "Remove the access config of the instance's network interface, and use Cloud NAT for outbound traffic.",
+		Gcloud:      "gcloud compute instances delete-access-config {resource} --project={project} --zone=<ZONE> --access-config-name=<ACCESS_CONFIG>",
+		References:  []string{"https://cloud.google.com/compute/docs/ip-addresses/reserve-static-external-ip-address"},
+	})
+	register("exposedPort", Entry{
+		ID:          "compute-exposed-port",
+		Severity:    High,
+		Rationale:   "Ports reachable from the internet expose the instance's services to scans and exploits.",
+		Remediation: "Restrict the source ranges of the firewall rules allowing the port, or remove the instance's external IP address.",
+		Gcloud:      "gcloud compute firewall-rules update <FIREWALL_RULE> --project={project} --source-ranges=<ALLOWED_RANGES>",
+		References:  []string{"https://cloud.google.com/vpc/docs/firewalls"},
+	})
+	register("internetExposure", Entry{
+		ID:          "compute-internet-exposure",
+		Severity:    High,
+		Rationale:   "Instances reachable from the internet expose their services to scans and exploits.",
+		Remediation: "Restrict the source ranges of the firewall rules applying to the instance, or remove its external IP address.",
+		References:  []string{"https://cloud.google.com/vpc/docs/firewalls"},
+	})
+
+	// Networking
+	register("sensitivePort", Entry{
+		ID:          "network-sensitive-port",
+		Severity:    Critical,
+		Rationale:   "Databases and control planes reachable from the internet are targeted by automated attacks and often lack strong authentication.",
+		Remediation: "Restrict the source ranges of the firewall rule to known addresses, or remove the port from the rule.",
+		Gcloud:      "gcloud compute firewall-rules update {resource} --project={project} --source-ranges=<ALLOWED_RANGES>",
+		References:  []string{"https://cloud.google.com/vpc/docs/firewalls"},
+	})
+
+	// Kubernetes Engine
+	register("disableLegacyMetadataAPI", Entry{
+		ID:          "gke-legacy-metadata-api",
+		Severity:    High,
+		Rationale:   "The legacy metadata API lets pods read the node's kubelet credentials and service account token.",
+		Remediation: "Recreate the node pool with the disable-legacy-endpoints metadata key set to true.",
+		Gcloud:      "gcloud container node-pools create <NODE_POOL> --project={project} --cluster=<CLUSTER> --zone=<ZONE> --metadata=disable-legacy-endpoints=true",
+		References:  []string{"https://cloud.google.com/kubernetes-engine/docs/how-to/protecting-cluster-metadata"},
+	})
+
+	// Storage
+	register("bucketPolicyOnly", Entry{
+		ID:          "storage-bucket-policy-only",
+		Severity:    Medium,
+		Rationale:   "Bucket Policy Only disables object ACLs, so access to every object is governed by the bucket's IAM policy.",
+		Remediation: "Enable Bucket Policy Only on the bucket, after moving object ACL grants to the bucket's IAM policy.",
+		Gcloud:      "gsutil bucketpolicyonly set on gs://{resource}",
+		References:  []string{"https://cloud.google.com/storage/docs/bucket-policy-only"},
+	})
+
+	// Logging
+	register("logBucketRetentionPolicy", Entry{
+		ID:          "logging-bucket-retention-policy",
+		Severity:    Medium,
+		Rationale:   "A locked retention policy prevents exported logs from being deleted before the retention period ends.",
+		Remediation: "Set a retention policy on the bucket and lock it.",
+		Gcloud:      "gsutil retention set <RETENTION_PERIOD> gs://<BUCKET> && gsutil retention lock gs://<BUCKET>",
+		References:  []string{"https://cloud.google.com/storage/docs/bucket-lock"},
+	})
+
+	// KMS
+	register("kmsKeyNotPublic", Entry{
+		ID:          "kms-key-not-public",
+		Severity:    Critical,
+		Rationale:   "Crypto keys accessible by allUsers or allAuthenticatedUsers can be used by anyone to encrypt or decrypt data.",
+		Remediation: "Remove allUsers and allAuthenticatedUsers from the crypto key's IAM policy.",
+		Gcloud:      "gcloud kms keys remove-iam-policy-binding {resource} --project={project} --keyring=<KEY_RING> --location=<LOCATION> --member=allUsers --role=<ROLE>",
+		References:  []string{"https://cloud.google.com/kms/docs/iam"},
+	})
+
+	// Organization policy
+	register("orgPolicyEnforced", Entry{
+		ID:          "orgpolicy-constraint-enforced",
+		Severity:    Medium,
+		Rationale:   "Organization policy constraints enforce guardrails that individual projects cannot weaken.",
+		Remediation: "Enforce the constraint on the organization, or remove the project level policy that overrides it.",
+		Gcloud:      "gcloud resource-manager org-policies enable-enforce <CONSTRAINT> --project={project}",
+		References:  []string{"https://cloud.google.com/resource-manager/docs/organization-policy/overview"},
+	})
+
+	// Collection
+	register("collected", Entry{
+		ID:          "collection-error",
+		Severity:    Medium,
+		Rationale:   "Resources that could not be collected are not audited, so their findings are missing from the reports.",
+		Remediation: "Grant the scanning identity read access to the resource type in the project, and enable its API.",
+		References:  []string{"https://cloud.google.com/iam/docs/understanding-roles"},
+	})
+}
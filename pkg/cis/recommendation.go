@@ -4,6 +4,8 @@ package cis
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // Recommendation is a CIS recommendation for GCP
@@ -26,6 +28,18 @@ type Recommendation struct {
 var (
 	// Registry is the registry of CIS recommendations
 	Registry = make(map[string]Recommendation, 1)
+
+	// Sections are the names of the benchmark sections, in the order of the benchmark. A recommendation belongs to the
+	// section numbered by the major part of its CIS ID
+	Sections = []string{
+		"Identity and Access Management",
+		"Logging and Monitoring",
+		"Networking",
+		"Virtual Machines",
+		"Storage",
+		"Cloud SQL Database Services",
+		"Kubernetes Engine",
+	}
 )
 
 // Marshal returns the JSON formatted bytes for a recommendation
@@ -42,6 +56,15 @@ func (r *Recommendation) Format() string {
 	return fmt.Sprintf("CIS %v - %v (%v)", r.CisID, r.Name, score)
 }
 
+// Section returns the name of the benchmark section the recommendation belongs to
+func (r *Recommendation) Section() string {
+	major, err := strconv.Atoi(strings.SplitN(r.CisID, ".", 2)[0])
+	if err != nil || major < 1 || major > len(Sections) {
+		return ""
+	}
+	return Sections[major-1]
+}
+
 func init() {
 	// IAM controls
 	Registry[iam1.CisID] = iam1
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/UnityTech/nemesis/pkg/utils"
+	"github.com/golang/glog"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	apiKeysEndpoint = "https://apikeys.googleapis.com/v2"
+	apiKeysScope    = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+type apiKeysListResponse struct {
+	Keys          []*gcp.ApiKey `json:"keys"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// listAPIKeys returns all API keys for a project. The Google API client library
+// does not include the API Keys service, so we query its REST endpoint directly
+func (c *Client) listAPIKeys(projectID string) ([]*gcp.ApiKey, error) {
+
+	keys := []*gcp.ApiKey{}
+	pageToken := ""
+
+	for {
+		u := fmt.Sprintf("%v/projects/%v/locations/global/keys", apiKeysEndpoint, projectID)
+		if pageToken != "" {
+			u = fmt.Sprintf("%v?pageToken=%v", u, url.QueryEscape(pageToken))
+		}
+
+		resp, err := c.apiKeysClient.Get(u)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := googleapi.CheckResponse(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var page apiKeysListResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, page.Keys...)
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	return keys, nil
+}
+
+// GetAPIKeyResources launches the process retrieving API keys
+func (c *Client) GetAPIKeyResources() error {
+
+	defer utils.Elapsed("GetAPIKeyResources")()
+
+	worker := func(projectIDs <-chan string, results chan<- apiKeyCallResult) {
+
+		id := <-projectIDs
+		res := apiKeyCallResult{ProjectID: id, Keys: []*gcp.ApiKeyResource{}}
+
+		// Check that the API Keys API is enabled. If not, don't audit API keys in the project
+		if !c.isServiceEnabled(id, "apikeys.googleapis.com") {
+			results <- res
+			return
+		}
+
+		keys, err := c.listAPIKeys(id)
+		if err != nil {
+			c.recordCollectionError(id, "api_key", err)
+			results <- res
+			return
+		}
+
+		for _, k := range keys {
+			res.Keys = append(res.Keys, gcp.NewApiKeyResource(k))
+		}
+
+		results <- res
+	}
+
+	// Setup worker pool
+	projectIDs := make(chan string, len(c.resourceprojects))
+	results := make(chan apiKeyCallResult, len(c.resourceprojects))
+	numWorkers := len(c.resourceprojects)
+	for w := 0; w < numWorkers; w++ {
+		go worker(projectIDs, results)
+	}
+
+	// Feed the workers and collect the API key info
+	for _, p := range c.resourceprojects {
+		projectIDs <- p.ProjectId
+	}
+
+	// Collect the info
+	for i := 0; i < numWorkers; i++ {
+		res := <-results
+		c.apiKeys[res.ProjectID] = res.Keys
+	}
+
+	return nil
+}
+
+type apiKeyCallResult struct {
+	ProjectID string
+	Keys      []*gcp.ApiKeyResource
+}
+
+// GenerateAPIKeyReports signals the client to process ApiKeyResource's for reports.
+// If there are no API keys found in the configuration, no reports will be created.
+func (c *Client) GenerateAPIKeyReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "api_key"
+
+	for _, p := range c.resourceprojects {
+		projectID := p.ProjectId
+
+		for _, k := range c.apiKeys[projectID] {
+			r := c.newReport(
+				typ,
+				projectID,
+				k.Name(),
+				fmt.Sprintf("Project %v API Key %v", projectID, k.Name()),
+			)
+			r.ResourceName = k.ResourceName()
+			if r.Data, err = k.Marshal(); err != nil {
+				glog.Fatalf("Failed to marshal API key: %v", err)
+			}
+
+			// API keys should not be used at all
+			noKeys := report.NewCISControl(
+				"1.10",
+				fmt.Sprintf("Project %v should not have API keys", projectID),
+			)
+			noKeys.Error = fmt.Sprintf("API key %v exists", k.Name())
+
+			// API keys should only be usable from specific hosts and apps
+			hostRestrictions := report.NewCISControl(
+				"1.11",
+				fmt.Sprintf("API key %v should be restricted to specific hosts and apps", k.Name()),
+			)
+			if err := k.IsRestrictedToHostsAndApps(); err != nil {
+				hostRestrictions.Error = err.Error()
+			} else {
+				hostRestrictions.Passed()
+			}
+
+			// API keys should only be usable with the APIs the application needs
+			apiRestrictions := report.NewCISControl(
+				"1.12",
+				fmt.Sprintf("API key %v should be restricted to specific APIs", k.Name()),
+			)
+			if err := k.IsRestrictedToAPIs(); err != nil {
+				apiRestrictions.Error = err.Error()
+			} else {
+				apiRestrictions.Passed()
+			}
+
+			// API keys should be rotated on a regular interval
+			rotation := report.NewCISControl(
+				"1.13",
+				fmt.Sprintf("API key %v should be rotated on a regular interval", k.Name()),
+			)
+			if err := k.NeedsRotation(c.cfg.IAM.APIKeyExpirationTime); err != nil {
+				rotation.Error = err.Error()
+			} else {
+				rotation.Passed()
+			}
+
+			r.AddControls(noKeys, hostRestrictions, apiRestrictions, rotation)
+
+			reports = append(reports, r)
+			c.incrementMetrics(&r)
+		}
+	}
+
+	return
+}
@@ -1,38 +1,75 @@
 package client
 
 import (
+	"github.com/UnityTech/nemesis/pkg/config"
+	"github.com/UnityTech/nemesis/pkg/report"
 	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/UnityTech/nemesis/pkg/version"
 
 	"github.com/golang/glog"
 
 	"context"
+	"net/http"
+	"sync"
+	"time"
 
 	logging "cloud.google.com/go/logging/apiv2"
 	push "github.com/prometheus/client_golang/prometheus/push"
+	cloudkms "google.golang.org/api/cloudkms/v1"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
 	compute "google.golang.org/api/compute/v1"
 	container "google.golang.org/api/container/v1"
+	dns "google.golang.org/api/dns/v1"
 	iam "google.golang.org/api/iam/v1"
 	serviceusage "google.golang.org/api/serviceusage/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 	storage "google.golang.org/api/storage/v1"
 )
 
 // Client is the client used for auditing Google Cloud Compute Engine resources
 type Client struct {
 
+	// Audit configuration
+	cfg      *config.Config
+	selector *report.Selector
+	profiles []*profile
+	waivers  []report.Waiver
+
 	// API clients
 	computeClient       *compute.Service
 	cloudResourceClient *cloudresourcemanager.Service
+	folderClient        *cloudresourcemanagerv2.Service
 	storageClient       *storage.Service
 	containerClient     *container.Service
 	serviceusageClient  *serviceusage.Service
 	iamClient           *iam.Service
+	sqlClient           *sqladmin.Service
+	dnsClient           *dns.Service
+	kmsClient           *cloudkms.Service
+	apiKeysClient       *http.Client
 	logConfigClient     *logging.ConfigClient
 	logMetricClient     *logging.MetricsClient
 
 	// Root project
 	resourceprojects []*cloudresourcemanager.Project
 
+	// Resource hierarchy
+	folders         map[string]*cloudresourcemanagerv2.Folder
+	hierarchy       map[string]*projectHierarchy
+	folderHierarchy map[string]*projectHierarchy
+
+	// Project labels, which apply to every resource in the project
+	projectLabels map[string]map[string]string
+
+	// Project numbers by project ID
+	projectNumbers map[string]string
+
+	// Scan run metadata, recorded in every report
+	runID    string
+	scanTime time.Time
+	version  string
+
 	// Resources
 	services         map[string][]*gcp.ServiceAPIResource
 	computeprojects  []*gcp.ComputeProjectResource
@@ -52,33 +89,75 @@ type Client struct {
 
 	// IAM Resources
 	policies        map[string]*gcp.IamPolicyResource
+	folderPolicies  map[string]*gcp.IamPolicyResource
 	serviceaccounts map[string][]*gcp.IamServiceAccountResource
 
 	// Logging resources
 	logSinks   map[string][]*gcp.LoggingSinkResource
 	logMetrics map[string][]*gcp.LoggingMetricResource
 
+	// SQL resources
+	sqlInstances map[string][]*gcp.SqlInstanceResource
+
+	// DNS resources
+	managedZones map[string][]*gcp.DNSManagedZoneResource
+
+	// KMS resources
+	keyRings   map[string][]*gcp.KMSKeyRingResource
+	cryptoKeys map[string][]*gcp.KMSCryptoKeyResource
+
+	// API key resources
+	apiKeys map[string][]*gcp.ApiKeyResource
+
+	// Organization policy resources
+	orgPolicies map[string][]*gcp.OrgPolicyResource
+
+	// Failures encountered while collecting resources
+	collectionErrors   []*collectionError
+	collectionErrorsMu sync.Mutex
+
 	// Metrics pusher
 	pusher           *push.Pusher
 	metricsArePushed bool
 }
 
 // New returns a new wrk conforming to the worker.W interface
-func New() *Client {
+func New(cfg *config.Config) *Client {
 	var cc *compute.Service
 	var crm *cloudresourcemanager.Service
+	var crmv2 *cloudresourcemanagerv2.Service
 	var cs *storage.Service
 	var con *container.Service
 	var su *serviceusage.Service
 	var i *iam.Service
+	var sql *sqladmin.Service
+	var d *dns.Service
+	var kms *cloudkms.Service
 	var lc *logging.ConfigClient
 	var lm *logging.MetricsClient
 
 	c := new(Client)
+	c.cfg = cfg
+	c.runID = newRunID()
+	c.scanTime = time.Now().UTC()
+	c.version = version.GetVersion().VersionNumber()
 	ctx := context.Background()
 
+	// Controls that are not selected are reported as skipped
+	selector, err := report.NewSelector(cfg.Controls.Include, cfg.Controls.Exclude)
+	if err != nil {
+		glog.Fatalf("Failed to configure control selection: %v", err)
+	}
+	c.selector = selector
+
+	// Policy profiles replace the control selection for resources with matching labels
+	if c.profiles, err = newProfiles(cfg.Profiles); err != nil {
+		glog.Fatalf("Failed to configure policy profiles: %v", err)
+	}
+	c.waivers = cfg.Waivers.Rules
+
 	// Create compute client
-	cc, err := compute.NewService(ctx)
+	cc, err = compute.NewService(ctx)
 	if err != nil {
 		glog.Fatalf("Failed to create Google Cloud Engine client: %v", err)
 	}
@@ -89,6 +168,12 @@ func New() *Client {
 		glog.Fatalf("Failed to create Google Cloud Resource Manager client: %v", err)
 	}
 
+	// Create cloudresourcemanager v2 client, for walking folders
+	crmv2, err = cloudresourcemanagerv2.NewService(ctx)
+	if err != nil {
+		glog.Fatalf("Failed to create Google Cloud Resource Manager folders client: %v", err)
+	}
+
 	// Create storage client
 	cs, err = storage.NewService(ctx)
 	if err != nil {
@@ -112,6 +197,21 @@ func New() *Client {
 		glog.Fatalf("Failed to create IAM client: %v", err)
 	}
 
+	sql, err = sqladmin.NewService(ctx)
+	if err != nil {
+		glog.Fatalf("Failed to create Cloud SQL client: %v", err)
+	}
+
+	d, err = dns.NewService(ctx)
+	if err != nil {
+		glog.Fatalf("Failed to create Cloud DNS client: %v", err)
+	}
+
+	kms, err = cloudkms.NewService(ctx)
+	if err != nil {
+		glog.Fatalf("Failed to create Cloud KMS client: %v", err)
+	}
+
 	lc, err = logging.NewConfigClient(ctx)
 	if err != nil {
 		glog.Fatalf("Failed to create logging config client: %v", err)
@@ -124,16 +224,26 @@ func New() *Client {
 
 	c.computeClient = cc
 	c.cloudResourceClient = crm
+	c.folderClient = crmv2
 	c.storageClient = cs
 	c.containerClient = con
 	c.serviceusageClient = su
 	c.iamClient = i
+	c.sqlClient = sql
+	c.dnsClient = d
+	c.kmsClient = kms
+	c.apiKeysClient = getOAuthClient(ctx, apiKeysScope)
 	c.logConfigClient = lc
 	c.logMetricClient = lm
 
 	// Services
 	c.resourceprojects = []*cloudresourcemanager.Project{}
 	c.computeprojects = []*gcp.ComputeProjectResource{}
+	c.folders = make(map[string]*cloudresourcemanagerv2.Folder, 1)
+	c.hierarchy = make(map[string]*projectHierarchy, 1)
+	c.folderHierarchy = make(map[string]*projectHierarchy, 1)
+	c.projectLabels = make(map[string]map[string]string, 1)
+	c.projectNumbers = make(map[string]string, 1)
 
 	// Resources
 	c.services = make(map[string][]*gcp.ServiceAPIResource, 1)
@@ -151,14 +261,29 @@ func New() *Client {
 
 	// IAM resources
 	c.policies = make(map[string]*gcp.IamPolicyResource, 1)
+	c.folderPolicies = make(map[string]*gcp.IamPolicyResource, 1)
 	c.serviceaccounts = make(map[string][]*gcp.IamServiceAccountResource, 1)
 
 	// Logging resources
 	c.logSinks = make(map[string][]*gcp.LoggingSinkResource, 1)
 	c.logMetrics = make(map[string][]*gcp.LoggingMetricResource, 1)
 
+	// SQL resources
+	c.sqlInstances = make(map[string][]*gcp.SqlInstanceResource, 1)
+
+	// DNS resources
+	c.managedZones = make(map[string][]*gcp.DNSManagedZoneResource, 1)
+
+	// KMS resources
+	c.keyRings = make(map[string][]*gcp.KMSKeyRingResource, 1)
+	c.cryptoKeys = make(map[string][]*gcp.KMSCryptoKeyResource, 1)
+
+	// API key resources
+	c.apiKeys = make(map[string][]*gcp.ApiKeyResource, 1)
+	c.orgPolicies = make(map[string][]*gcp.OrgPolicyResource, 1)
+
 	// Configure metrics
-	c.pusher = configureMetrics()
+	c.pusher = configureMetrics(cfg.Metrics)
 
 	return c
 }
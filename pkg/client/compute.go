@@ -1,7 +1,9 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/UnityTech/nemesis/pkg/report"
 	"github.com/UnityTech/nemesis/pkg/resource/gcp"
@@ -71,9 +73,10 @@ func (c *Client) GetComputeResources() error {
 	// Get list of all projects.
 	projects := c.resourceprojects
 
+	// Without the list of zones, instances cannot be listed. Project level compute resources are still collected
 	zoneNames, err := c.getZoneNames()
 	if err != nil {
-		glog.Fatalf("%v", err)
+		c.recordCollectionError("", "compute_instance", err)
 	}
 
 	// Create a worker pool for querying zones a bit faster
@@ -86,7 +89,9 @@ func (c *Client) GetComputeResources() error {
 			instanceResources := []*gcp.ComputeInstanceResource{}
 			res, err := c.computeClient.Instances.List(projectID, z).Do()
 			if err != nil {
-				glog.Fatalf("Error retrieving project %v's instances in zone %v: %v", projectID, z, err)
+				c.recordCollectionError(projectID, "compute_instance", fmt.Errorf("Error retrieving instances in zone %v: %v", z, err))
+				results <- instanceResources
+				continue
 			}
 
 			// Create the resource
@@ -113,7 +118,8 @@ func (c *Client) GetComputeResources() error {
 		// Get the compute API's version of the project
 		project, err := c.computeClient.Projects.Get(projectID).Do()
 		if err != nil {
-			glog.Fatalf("Error retrieving project %v's metadata: %v", projectID, err)
+			c.recordCollectionError(projectID, "compute_metadata", err)
+			continue
 		}
 
 		// Store the project resource
@@ -161,7 +167,7 @@ func (c *Client) GenerateComputeMetadataReports() (reports []report.Report, err
 	for _, p := range c.computeprojects {
 		projectID := p.Name()
 		projectMetadata := c.computeMetadatas[projectID]
-		r := report.NewReport(typ, fmt.Sprintf("Project %v Common Instance Metadata", projectID))
+		r := c.newReport(typ, projectID, projectID, fmt.Sprintf("Project %v Common Instance Metadata", projectID))
 
 		// Always connect the data for the report with the source data
 		if r.Data, err = projectMetadata.Marshal(); err != nil {
@@ -198,6 +204,9 @@ func (c *Client) GenerateComputeMetadataReports() (reports []report.Report, err
 			}
 		}
 
+		// OS Login is enabled on every instance when the organization policy requires it
+		c.passIfOrgPolicyEnforced(&osLogin, projectID, "compute.requireOsLogin")
+
 		// Dynamic serial port access should be denied
 		// serial-port-enable is a special case, where absence of the key is equivalent to disabling serial port access
 		serialPortAccess := report.NewCISControl(
@@ -240,7 +249,7 @@ func (c *Client) GenerateComputeMetadataReports() (reports []report.Report, err
 
 		// Append the resource's report to our final list
 		reports = append(reports, r)
-		c.incrementMetrics(typ, projectID, r.Status(), projectID)
+		c.incrementMetrics(&r)
 	}
 
 	return
@@ -260,18 +269,21 @@ func (c *Client) GenerateComputeInstanceReports() (reports []report.Report, err
 		metadata := c.computeMetadatas[projectID]
 
 		for _, i := range instanceResources {
-			r := report.NewReport(typ, fmt.Sprintf("Project %v Compute Instance %v", projectID, i.Name()))
+			r := c.newReport(typ, projectID, i.Name(), fmt.Sprintf("Project %v Compute Instance %v", projectID, i.Name()))
+			r.ResourceName = i.ResourceName()
+			r.Location = i.Location()
+			c.labelReport(&r, i.Labels())
 			if r.Data, err = i.Marshal(); err != nil {
 				glog.Fatalf("Failed to marshal compute instance: %v", err)
 			}
 
 			// Make sure the number of Network Interfaces matches what is expected
 			numNicsControl := report.NewControl(
-				fmt.Sprintf("numNetworkInterfaces=%v", *flagComputeInstanceNumInterfaces),
-				fmt.Sprintf("Compute Instance should have a number of network interfaces equal to %v", *flagComputeInstanceNumInterfaces),
+				fmt.Sprintf("numNetworkInterfaces=%v", c.cfg.Compute.Instance.NumInterfaces),
+				fmt.Sprintf("Compute Instance should have a number of network interfaces equal to %v", c.cfg.Compute.Instance.NumInterfaces),
 			)
 
-			_, err := i.HasNumNetworkInterfaces(*flagComputeInstanceNumInterfaces)
+			_, err := i.HasNumNetworkInterfaces(c.cfg.Compute.Instance.NumInterfaces)
 			if err != nil {
 				numNicsControl.Error = err.Error()
 			} else {
@@ -280,11 +292,11 @@ func (c *Client) GenerateComputeInstanceReports() (reports []report.Report, err
 
 			// Measure whether a NAT ip address is expected
 			natIPControl := report.NewControl(
-				fmt.Sprintf("hasNatIP=%v", *flagComputeInstanceAllowNat),
-				fmt.Sprintf("Compute Instance should have a NAT ip configured: %v", *flagComputeInstanceAllowNat),
+				fmt.Sprintf("hasNatIP=%v", c.cfg.Compute.Instance.AllowNat),
+				fmt.Sprintf("Compute Instance should have a NAT ip configured: %v", c.cfg.Compute.Instance.AllowNat),
 			)
 			if i.HasNatIP() {
-				if *flagComputeInstanceAllowNat {
+				if c.cfg.Compute.Instance.AllowNat {
 					// External IP exists, and we want it to exist
 					natIPControl.Passed()
 				} else {
@@ -293,7 +305,7 @@ func (c *Client) GenerateComputeInstanceReports() (reports []report.Report, err
 				}
 			} else {
 				// External IP does not exist, and we don't want it to exist
-				if !*flagComputeInstanceAllowNat {
+				if !c.cfg.Compute.Instance.AllowNat {
 					natIPControl.Passed()
 				} else {
 					// It doesn't exist but we wanted it to exist
@@ -352,6 +364,7 @@ func (c *Client) GenerateComputeInstanceReports() (reports []report.Report, err
 					glog.Fatalf("Could not determine the state of instance %v's metadata, aborting...", projectID)
 				}
 			}
+			c.passIfOrgPolicyEnforced(&osLogin, projectID, "compute.requireOsLogin")
 
 			// Dynamic serial port access should be denied
 			// serial-port-enable is a special case, where absence of the key is equivalent to disabling serial port access
@@ -382,7 +395,7 @@ func (c *Client) GenerateComputeInstanceReports() (reports []report.Report, err
 			if !i.HasIPForwardingEnabled() {
 				ipForwarding.Passed()
 			} else {
-				if *flagComputeInstanceAllowIPForwarding {
+				if c.cfg.Compute.Instance.AllowIPForwarding {
 					ipForwarding.Passed()
 				} else {
 					ipForwarding.Error = "Compute Instance allows IP Forwarding"
@@ -413,7 +426,64 @@ func (c *Client) GenerateComputeInstanceReports() (reports []report.Report, err
 			// Add the instance resource report to the final list of reports
 			reports = append(reports, r)
 			totalResourcesCounter.Inc()
-			c.incrementMetrics(typ, i.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
+		}
+	}
+
+	return
+}
+
+// GenerateInstanceExposureReports signals the client to join ComputeInstanceResource's with the firewall rules of their
+// project, and report every port of an instance with an external ip that is reachable from the internet
+func (c *Client) GenerateInstanceExposureReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "instance_exposure"
+
+	for _, p := range c.computeprojects {
+
+		projectID := p.Name()
+		firewalls := c.firewalls[projectID]
+
+		for _, i := range c.instances[projectID] {
+
+			// Instances without an external ip cannot be reached from the internet
+			if !i.HasNatIP() {
+				continue
+			}
+
+			exposure := i.Exposure(firewalls)
+
+			r := c.newReport(typ, projectID, i.Name(), fmt.Sprintf("Project %v Compute Instance %v Internet Exposure", projectID, i.Name()))
+			r.ResourceName = i.ResourceName()
+			r.Location = i.Location()
+			c.labelReport(&r, i.Labels())
+			if r.Data, err = json.Marshal(exposure); err != nil {
+				glog.Fatalf("Failed to marshal instance exposure: %v", err)
+			}
+
+			// Each port range reachable from the internet is reported on its own
+			for _, port := range exposure.ExposedPorts {
+				exposedControl := report.NewControl(
+					fmt.Sprintf("exposedPort=%v", port),
+					fmt.Sprintf("Compute Instance should not be reachable from the internet on %v", port),
+				)
+				exposedControl.Error = fmt.Sprintf("%v is reachable from 0.0.0.0/0 on %v through firewall rules: %v",
+					port, strings.Join(exposure.ExternalIPs, ", "), strings.Join(port.Rules, ", "))
+				r.AddControls(exposedControl)
+			}
+
+			if len(exposure.ExposedPorts) == 0 {
+				notExposedControl := report.NewControl(
+					"internetExposure",
+					"Compute Instance should not be reachable from the internet",
+				)
+				notExposedControl.Passed()
+				r.AddControls(notExposedControl)
+			}
+
+			reports = append(reports, r)
+			c.incrementMetrics(&r)
 		}
 	}
 
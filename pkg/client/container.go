@@ -28,7 +28,7 @@ func (c *Client) GetContainerResources() error {
 	worker := func(projectIDs <-chan string, results chan<- containerCallResult) {
 
 		id := <-projectIDs
-		res := containerCallResult{ProjectID: id, Clusters: []*gcp.ContainerClusterResource{}}
+		res := containerCallResult{ProjectID: id, Clusters: []*gcp.ContainerClusterResource{}, NodePools: []*gcp.ContainerNodePoolResource{}}
 
 		// Check that the container API is enabled. If not, don't audit container resources in the project
 		if !c.isServiceEnabled(id, "container.googleapis.com") {
@@ -40,11 +40,17 @@ func (c *Client) GetContainerResources() error {
 		location := fmt.Sprintf("projects/%v/locations/-", id)
 		clusters, err := clustersService.List(location).Do()
 		if err != nil {
-			glog.Fatalf("Error retrieving container clusters in project %v: %v", id, err)
+			c.recordCollectionError(id, "container_cluster", err)
+			results <- res
+			return
 		}
 
 		for _, cluster := range clusters.Clusters {
-			res.Clusters = append(res.Clusters, gcp.NewContainerClusterResource(cluster))
+			clusterResource := gcp.NewContainerClusterResource(cluster)
+			res.Clusters = append(res.Clusters, clusterResource)
+
+			// Node pools are returned as part of the cluster, so link each one back to its parent
+			res.NodePools = append(res.NodePools, clusterResource.NodePools()...)
 		}
 
 		results <- res
@@ -67,6 +73,7 @@ func (c *Client) GetContainerResources() error {
 	for i := 0; i < numWorkers; i++ {
 		res := <-results
 		c.clusters[res.ProjectID] = res.Clusters
+		c.nodepools[res.ProjectID] = res.NodePools
 	}
 
 	return nil
@@ -75,6 +82,7 @@ func (c *Client) GetContainerResources() error {
 type containerCallResult struct {
 	ProjectID string
 	Clusters  []*gcp.ContainerClusterResource
+	NodePools []*gcp.ContainerNodePoolResource
 }
 
 // GenerateContainerClusterReports signals the client to process ContainerClusterResource's for reports.
@@ -86,10 +94,15 @@ func (c *Client) GenerateContainerClusterReports() (reports []report.Report, err
 	for _, p := range c.computeprojects {
 		projectID := p.Name()
 		for _, cluster := range c.clusters[projectID] {
-			r := report.NewReport(
+			r := c.newReport(
 				typ,
+				projectID,
+				cluster.Name(),
 				fmt.Sprintf("Project %v Container Cluster %v", projectID, cluster.Name()),
 			)
+			r.ResourceName = cluster.ResourceName()
+			r.Location = cluster.Location()
+			c.labelReport(&r, cluster.Labels())
 			if r.Data, err = cluster.Marshal(); err != nil {
 				glog.Fatalf("Failed to marshal container cluster: %v", err)
 			}
@@ -230,7 +243,7 @@ func (c *Client) GenerateContainerClusterReports() (reports []report.Report, err
 				"7.18",
 				fmt.Sprintf("Cluster %v should be launched with minimal OAuth scopes", cluster.Name()),
 			)
-			if _, err := cluster.IsUsingMinimalOAuthScopes(); err != nil {
+			if _, err := cluster.IsUsingMinimalOAuthScopes(c.cfg.Container.OAuthScopes); err != nil {
 				oauthScopes.Error = err.Error()
 			} else {
 				oauthScopes.Passed()
@@ -238,7 +251,7 @@ func (c *Client) GenerateContainerClusterReports() (reports []report.Report, err
 
 			r.AddControls(sdLogging, sdMonitoring, abac, masterAuthNetworks, dashboard, masterAuthPassword, networkPolicy, clientCert, aliasIps, privateMaster, privateNodes, defaultSA, oauthScopes)
 			reports = append(reports, r)
-			c.incrementMetrics(typ, cluster.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 	}
 
@@ -253,10 +266,14 @@ func (c *Client) GenerateContainerNodePoolReports() (reports []report.Report, er
 	for _, p := range c.computeprojects {
 		projectID := p.Name()
 		for _, nodepool := range c.nodepools[projectID] {
-			r := report.NewReport(
+			r := c.newReport(
 				typ,
-				fmt.Sprintf("Project %v Container Node Pool %v", projectID, nodepool.Name()),
+				projectID,
+				nodepool.Name(),
+				fmt.Sprintf("Project %v Container Cluster %v (%v) Node Pool %v", projectID, nodepool.ClusterName(), nodepool.Location(), nodepool.Name()),
 			)
+			r.ResourceName = nodepool.ResourceName()
+			r.Location = nodepool.Location()
 			if r.Data, err = nodepool.Marshal(); err != nil {
 				glog.Fatalf("Failed to marshal container node pool: %v", err)
 			}
@@ -307,7 +324,7 @@ func (c *Client) GenerateContainerNodePoolReports() (reports []report.Report, er
 
 			r.AddControls(legacyAPI, repair, upgrade, cos)
 			reports = append(reports, r)
-			c.incrementMetrics(typ, nodepool.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 	}
 
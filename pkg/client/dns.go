@@ -0,0 +1,154 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/UnityTech/nemesis/pkg/utils"
+	"github.com/golang/glog"
+	dns "google.golang.org/api/dns/v1"
+)
+
+// GetDNSResources launches the process retrieving Cloud DNS managed zones
+func (c *Client) GetDNSResources() error {
+
+	defer utils.Elapsed("GetDNSResources")()
+
+	worker := func(projectIDs <-chan string, results chan<- dnsCallResult) {
+
+		id := <-projectIDs
+		res := dnsCallResult{ProjectID: id, ManagedZones: []*gcp.DNSManagedZoneResource{}}
+
+		// Check that the DNS API is enabled. If not, don't audit DNS resources in the project
+		if !c.isServiceEnabled(id, "dns.googleapis.com") {
+			results <- res
+			return
+		}
+
+		var zones *dns.ManagedZonesListResponse
+
+		zones, err := c.dnsClient.ManagedZones.List(id).Do()
+		if err != nil {
+			c.recordCollectionError(id, "dns_managed_zone", err)
+			results <- res
+			return
+		}
+
+		for _, z := range zones.ManagedZones {
+			res.ManagedZones = append(res.ManagedZones, gcp.NewDNSManagedZoneResource(z))
+		}
+
+		for zones.NextPageToken != "" {
+			zones, err = c.dnsClient.ManagedZones.List(id).PageToken(zones.NextPageToken).Do()
+			if err != nil {
+				c.recordCollectionError(id, "dns_managed_zone", err)
+				break
+			}
+
+			for _, z := range zones.ManagedZones {
+				res.ManagedZones = append(res.ManagedZones, gcp.NewDNSManagedZoneResource(z))
+			}
+		}
+
+		results <- res
+	}
+
+	// Setup worker pool
+	projectIDs := make(chan string, len(c.resourceprojects))
+	results := make(chan dnsCallResult, len(c.resourceprojects))
+	numWorkers := len(c.resourceprojects)
+	for w := 0; w < numWorkers; w++ {
+		go worker(projectIDs, results)
+	}
+
+	// Feed the workers and collect the DNS info
+	for _, p := range c.resourceprojects {
+		projectIDs <- p.ProjectId
+	}
+
+	// Collect the info
+	for i := 0; i < numWorkers; i++ {
+		res := <-results
+		c.managedZones[res.ProjectID] = res.ManagedZones
+	}
+
+	return nil
+}
+
+type dnsCallResult struct {
+	ProjectID    string
+	ManagedZones []*gcp.DNSManagedZoneResource
+}
+
+// GenerateDNSManagedZoneReports signals the client to process DNSManagedZoneResource's for reports.
+// If there are no managed zones found in the configuration, no reports will be created.
+func (c *Client) GenerateDNSManagedZoneReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "dns_managed_zone"
+
+	for _, p := range c.resourceprojects {
+		projectID := p.ProjectId
+
+		for _, z := range c.managedZones[projectID] {
+			r := c.newReport(
+				typ,
+				projectID,
+				z.Name(),
+				fmt.Sprintf("Project %v DNS Managed Zone %v", projectID, z.Name()),
+			)
+			r.ResourceName = fmt.Sprintf("//dns.googleapis.com/projects/%v/managedZones/%v", projectID, z.Name())
+			c.labelReport(&r, z.Labels())
+			if r.Data, err = z.Marshal(); err != nil {
+				glog.Fatalf("Failed to marshal DNS managed zone: %v", err)
+			}
+
+			dnssecControl := report.NewCISControl(
+				"3.3",
+				fmt.Sprintf("Managed zone %v should have DNSSEC enabled", z.Name()),
+			)
+			keySigningControl := report.NewCISControl(
+				"3.4",
+				fmt.Sprintf("Managed zone %v should not use RSASHA1 for the key-signing key", z.Name()),
+			)
+			zoneSigningControl := report.NewCISControl(
+				"3.5",
+				fmt.Sprintf("Managed zone %v should not use RSASHA1 for the zone-signing key", z.Name()),
+			)
+
+			// DNSSEC cannot be enabled for private zones, so the controls do not apply
+			if z.IsPrivate() {
+				reason := fmt.Sprintf("Managed zone %v is private", z.Name())
+				dnssecControl.NotApplicable(reason)
+				keySigningControl.NotApplicable(reason)
+				zoneSigningControl.NotApplicable(reason)
+			} else {
+				if z.IsDNSSECEnabled() {
+					dnssecControl.Passed()
+				} else {
+					dnssecControl.Error = "DNSSEC is not enabled"
+				}
+
+				if err := z.KeySigningKeyUsesRSASHA1(); err != nil {
+					keySigningControl.Error = err.Error()
+				} else {
+					keySigningControl.Passed()
+				}
+
+				if err := z.ZoneSigningKeyUsesRSASHA1(); err != nil {
+					zoneSigningControl.Error = err.Error()
+				} else {
+					zoneSigningControl.Passed()
+				}
+			}
+
+			r.AddControls(dnssecControl, keySigningControl, zoneSigningControl)
+
+			reports = append(reports, r)
+			c.incrementMetrics(&r)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/golang/glog"
+)
+
+// collectionError is a failure to collect a type of resource from a project
+type collectionError struct {
+	ProjectID    string `json:"projectId"`
+	ResourceType string `json:"resourceType"`
+	Error        string `json:"error"`
+}
+
+// recordCollectionError captures a failure to collect resources so that the scan can continue
+// with partial results. Safe to call from collection workers
+func (c *Client) recordCollectionError(projectID string, resourceType string, err error) {
+	glog.Errorf("Failed to collect %v resources from project %v: %v", resourceType, projectID, err)
+
+	c.collectionErrorsMu.Lock()
+	defer c.collectionErrorsMu.Unlock()
+
+	c.collectionErrors = append(c.collectionErrors, &collectionError{
+		ProjectID:    projectID,
+		ResourceType: resourceType,
+		Error:        err.Error(),
+	})
+	collectionErrorsCounter.WithLabelValues(resourceType, projectID).Inc()
+}
+
+// GenerateCollectionErrorReports signals the client to report every failure that occurred while collecting resources
+func (c *Client) GenerateCollectionErrorReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "collection_error"
+
+	for _, e := range c.collectionErrors {
+		title := fmt.Sprintf("Project %v %v Collection Error", e.ProjectID, e.ResourceType)
+		if e.ProjectID == "" {
+			// The failure was not specific to a single project
+			title = fmt.Sprintf("%v Collection Error", e.ResourceType)
+		}
+
+		r := c.newReport(typ, e.ProjectID, e.ResourceType, title)
+		if r.Data, err = json.Marshal(e); err != nil {
+			glog.Fatalf("Failed to marshal collection error: %v", err)
+		}
+
+		collected := report.NewControl(
+			fmt.Sprintf("collected=%v", e.ResourceType),
+			fmt.Sprintf("Resources of type %v should be collected from the project", e.ResourceType),
+		)
+		collected.Error = e.Error
+
+		r.AddControls(collected)
+		reports = append(reports, r)
+		c.incrementMetrics(&r)
+	}
+
+	return
+}
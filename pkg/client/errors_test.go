@@ -0,0 +1,22 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCollectionErrorReports(t *testing.T) {
+
+	c := &Client{}
+	c.recordCollectionError("my-project", "storage_bucket", errors.New("googleapi: Error 403: Access denied"))
+
+	reports, err := c.GenerateCollectionErrorReports()
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "collection_error", reports[0].Type)
+	assert.Equal(t, report.Failed, reports[0].Status())
+	assert.Equal(t, "googleapi: Error 403: Access denied", reports[0].Controls[0].Error)
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
+)
+
+const (
+	organizationsPrefix = "organizations/"
+	foldersPrefix       = "folders/"
+	projectsPrefix      = "projects/"
+)
+
+// projectHierarchy describes where a project resides in the resource hierarchy
+type projectHierarchy struct {
+	OrganizationID string
+	FolderPath     string
+
+	// Resource names of the organization and folders above the project, starting from the organization
+	Ancestors []string
+}
+
+// parseScope splits a scope such as organizations/123 or folders/456 into the parent type and ID used by the Projects API
+func parseScope(scope string) (parentType string, parentID string, err error) {
+	switch {
+	case strings.HasPrefix(scope, organizationsPrefix):
+		parentType, parentID = "organization", strings.TrimPrefix(scope, organizationsPrefix)
+	case strings.HasPrefix(scope, foldersPrefix):
+		parentType, parentID = "folder", strings.TrimPrefix(scope, foldersPrefix)
+	default:
+		err = fmt.Errorf("Invalid scope '%v', expected organizations/<id> or folders/<id>", scope)
+		return
+	}
+
+	if parentID == "" {
+		err = fmt.Errorf("Invalid scope '%v', missing ID", scope)
+	}
+	return
+}
+
+// parseExcludes parses a list of folders and projects to exclude from the scan.
+// Projects may be given either as projects/<id> or as a bare project ID
+func parseExcludes(list []string) map[string]bool {
+	excludes := map[string]bool{}
+	for _, e := range list {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if !strings.HasPrefix(e, foldersPrefix) && !strings.HasPrefix(e, projectsPrefix) {
+			e = projectsPrefix + e
+		}
+		excludes[e] = true
+	}
+	return excludes
+}
+
+// isExcluded returns whether one of the organizations or folders above a project is excluded
+func (h *projectHierarchy) isExcluded(excludes map[string]bool) bool {
+	for _, a := range h.Ancestors {
+		if excludes[a] {
+			return true
+		}
+	}
+	return false
+}
+
+// listScopeProjects walks the resource hierarchy beneath a scope and returns every ACTIVE project that is not excluded
+func (c *Client) listScopeProjects(scope string, excludes map[string]bool) ([]*cloudresourcemanager.Project, error) {
+
+	if _, _, err := parseScope(scope); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	projects := []*cloudresourcemanager.Project{}
+	parents := []string{scope}
+
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+		parentType, parentID, _ := parseScope(parent)
+
+		// Collect the projects directly beneath this parent
+		filter := fmt.Sprintf("parent.type:%v parent.id:%v lifecycleState:ACTIVE", parentType, parentID)
+		if c.cfg.Projects.Filter != "" {
+			filter = fmt.Sprintf("%v name:%v", filter, c.cfg.Projects.Filter)
+		}
+		err := c.cloudResourceClient.Projects.List().Filter(filter).Pages(ctx, func(page *cloudresourcemanager.ListProjectsResponse) error {
+			projects = append(projects, page.Projects...)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving projects beneath %v: %v", parent, err)
+		}
+
+		// Descend into the active folders beneath this parent
+		err = c.folderClient.Folders.List().Parent(parent).Pages(ctx, func(page *cloudresourcemanagerv2.ListFoldersResponse) error {
+			for _, f := range page.Folders {
+				if f.LifecycleState != "ACTIVE" || excludes[f.Name] {
+					continue
+				}
+				c.folders[f.Name] = f
+				parents = append(parents, f.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving folders beneath %v: %v", parent, err)
+		}
+	}
+
+	return projects, nil
+}
+
+// getFolder returns a folder from the resource hierarchy, retrieving it if it was not seen before
+func (c *Client) getFolder(name string) (*cloudresourcemanagerv2.Folder, error) {
+	if f, ok := c.folders[name]; ok {
+		return f, nil
+	}
+
+	f, err := c.folderClient.Folders.Get(name).Do()
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving folder %v: %v", name, err)
+	}
+	c.folders[name] = f
+	return f, nil
+}
+
+// resolveHierarchy walks up from a project to its organization, recording the display names of the folders it passes
+func (c *Client) resolveHierarchy(p *cloudresourcemanager.Project) (*projectHierarchy, error) {
+
+	h := &projectHierarchy{}
+	if p.Parent == nil {
+		return h, nil
+	}
+
+	folders := []string{}
+	parent := fmt.Sprintf("%vs/%v", p.Parent.Type, p.Parent.Id)
+	for strings.HasPrefix(parent, foldersPrefix) {
+		f, err := c.getFolder(parent)
+		if err != nil {
+			return nil, err
+		}
+		folders = append([]string{f.DisplayName}, folders...)
+		h.Ancestors = append([]string{f.Name}, h.Ancestors...)
+		parent = f.Parent
+	}
+
+	if strings.HasPrefix(parent, organizationsPrefix) {
+		h.OrganizationID = strings.TrimPrefix(parent, organizationsPrefix)
+		h.Ancestors = append([]string{parent}, h.Ancestors...)
+	}
+	h.FolderPath = strings.Join(folders, "/")
+	return h, nil
+}
+
+// newReport returns a new report for a named resource in the given project, annotated with the project's place in the resource
+// hierarchy, its labels and the scan run. Controls added to the report that are not selected by the control selection are
+// skipped, and failed controls covered by a waiver are waived. The report's resource name and location default to those of
+// the project, and are set by the caller for resources within the project
+func (c *Client) newReport(typ string, projectID string, name string, title string) report.Report {
+	r := report.NewReport(typ, title)
+	r.ProjectID = projectID
+	r.ProjectNumber = c.projectNumbers[projectID]
+	r.Resource = name
+	if projectID != "" {
+		r.ResourceName = gcp.ProjectResourceName(projectID)
+		r.Location = gcp.GlobalLocation
+	}
+	r.RunID = c.runID
+	r.Timestamp = c.scanTime
+	r.Version = c.version
+	r.WaiveControls(c.waivers)
+	c.labelReport(&r, c.projectLabels[projectID])
+	if h, ok := c.hierarchy[projectID]; ok {
+		r.OrganizationID = h.OrganizationID
+		r.FolderPath = h.FolderPath
+	}
+	return r
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
+)
+
+func TestParseScope(t *testing.T) {
+
+	typ, id, err := parseScope("organizations/123")
+	assert.Nil(t, err)
+	assert.Equal(t, "organization", typ)
+	assert.Equal(t, "123", id)
+
+	typ, id, err = parseScope("folders/456")
+	assert.Nil(t, err)
+	assert.Equal(t, "folder", typ)
+	assert.Equal(t, "456", id)
+
+	for _, invalid := range []string{"", "123", "projects/my-project", "folders/"} {
+		_, _, err = parseScope(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestParseExcludes(t *testing.T) {
+	excludes := parseExcludes([]string{"folders/456", " my-project", "projects/other-project", ""})
+	assert.Equal(t, map[string]bool{
+		"folders/456":            true,
+		"projects/my-project":    true,
+		"projects/other-project": true,
+	}, excludes)
+
+	// Projects are excluded through any of their folders
+	h := &projectHierarchy{Ancestors: []string{"organizations/123", "folders/456", "folders/789"}}
+	assert.True(t, h.isExcluded(excludes))
+	h = &projectHierarchy{Ancestors: []string{"organizations/123", "folders/789"}}
+	assert.False(t, h.isExcluded(excludes))
+}
+
+func TestResolveHierarchy(t *testing.T) {
+
+	c := &Client{
+		folders: map[string]*cloudresourcemanagerv2.Folder{
+			"folders/1": {Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/123"},
+			"folders/2": {Name: "folders/2", DisplayName: "Platform", Parent: "folders/1"},
+		},
+		hierarchy: map[string]*projectHierarchy{},
+	}
+
+	h, err := c.resolveHierarchy(&cloudresourcemanager.Project{
+		ProjectId: "my-project",
+		Parent:    &cloudresourcemanager.ResourceId{Type: "folder", Id: "2"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &projectHierarchy{
+		OrganizationID: "123",
+		FolderPath:     "Engineering/Platform",
+		Ancestors:      []string{"organizations/123", "folders/1", "folders/2"},
+	}, h)
+
+	// Projects directly beneath the organization have no folder path
+	h, err = c.resolveHierarchy(&cloudresourcemanager.Project{
+		ProjectId: "org-project",
+		Parent:    &cloudresourcemanager.ResourceId{Type: "organization", Id: "123"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &projectHierarchy{OrganizationID: "123", Ancestors: []string{"organizations/123"}}, h)
+
+	// Reports are annotated with the project's hierarchy and the scan run
+	c.hierarchy["my-project"] = &projectHierarchy{OrganizationID: "123", FolderPath: "Engineering/Platform"}
+	c.projectNumbers = map[string]string{"my-project": "1234567890"}
+	c.runID = newRunID()
+	c.scanTime = time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+	c.version = "0.0.1-dev"
+	r := c.newReport("storage_bucket", "my-project", "b", "Project my-project Storage Bucket b")
+	assert.Equal(t, "my-project", r.ProjectID)
+	assert.Equal(t, "1234567890", r.ProjectNumber)
+	assert.Equal(t, "b", r.Resource)
+	assert.Equal(t, "//cloudresourcemanager.googleapis.com/projects/my-project", r.ResourceName)
+	assert.Equal(t, "global", r.Location)
+	assert.Equal(t, "123", r.OrganizationID)
+	assert.Equal(t, "Engineering/Platform", r.FolderPath)
+	assert.Equal(t, c.runID, r.RunID)
+	assert.Equal(t, c.scanTime, r.Timestamp)
+	assert.Equal(t, "0.0.1-dev", r.Version)
+}
+
+func TestNewRunID(t *testing.T) {
+	id := newRunID()
+	assert.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", id)
+	assert.NotEqual(t, id, newRunID())
+}
@@ -2,12 +2,15 @@ package client
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/UnityTech/nemesis/pkg/report"
 	"github.com/UnityTech/nemesis/pkg/resource/gcp"
 	"github.com/UnityTech/nemesis/pkg/utils"
 	"github.com/golang/glog"
 	"google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
 )
 
 // GetIamResources gathers the list of IAM resources for the projects
@@ -24,14 +27,18 @@ func (c *Client) GetIamResources() error {
 		req := cloudresourcemanager.GetIamPolicyRequest{}
 		policy, err := c.cloudResourceClient.Projects.GetIamPolicy(id, &req).Do()
 		if err != nil {
-			glog.Fatalf("Failed to retrieve IAM policy for project %v: %v", id, err)
+			c.recordCollectionError(id, "iam_policy", err)
+			results <- res
+			return
 		}
 
 		res.Policy = gcp.NewIamPolicyResource(policy)
 
 		saList, err := c.iamClient.Projects.ServiceAccounts.List(projectID).Do()
 		if err != nil {
-			glog.Fatalf("Failed to retrieve service accounts from project %v: %v", id, err)
+			c.recordCollectionError(id, "iam_service_account", err)
+			results <- res
+			return
 		}
 
 		for _, a := range saList.Accounts {
@@ -40,10 +47,13 @@ func (c *Client) GetIamResources() error {
 			saKeySearch := fmt.Sprintf("%v/serviceAccounts/%v", projectID, a.UniqueId)
 			keys, err := c.iamClient.Projects.ServiceAccounts.Keys.List(saKeySearch).KeyTypes("USER_MANAGED").Do()
 			if err != nil {
-				glog.Fatalf("Failed to retrieve service account keys from project %v: %v", id, err)
-			}
-			for _, k := range keys.Keys {
-				acct.Keys = append(acct.Keys, k)
+				// The account is still reported, with its key controls failing on the collection error
+				c.recordCollectionError(id, "iam_service_account_key", err)
+				acct.KeysError = fmt.Errorf("Error retrieving keys of service account %v: %v", a.Email, err)
+			} else {
+				for _, k := range keys.Keys {
+					acct.Keys = append(acct.Keys, k)
+				}
 			}
 
 			res.ServiceAccounts = append(res.ServiceAccounts, acct)
@@ -68,13 +78,81 @@ func (c *Client) GetIamResources() error {
 	// Collect the info
 	for i := 0; i < numWorkers; i++ {
 		res := <-results
-		c.policies[res.ProjectID] = res.Policy
+		if res.Policy != nil {
+			c.policies[res.ProjectID] = res.Policy
+		}
 		c.serviceaccounts[res.ProjectID] = res.ServiceAccounts
 	}
 
+	// Collect the policies of the organizations and folders that projects inherit bindings from
+	for _, name := range c.ancestors() {
+		policy, err := c.getAncestorIamPolicy(name)
+		if err != nil {
+			c.recordCollectionError("", "iam_policy", fmt.Errorf("Failed to retrieve IAM policy for %v: %v", name, err))
+			continue
+		}
+		c.folderPolicies[name] = policy
+
+		// A folder's place in the hierarchy is resolved the same way as a project beneath it
+		if strings.HasPrefix(name, foldersPrefix) {
+			h, err := c.resolveHierarchy(&cloudresourcemanager.Project{
+				Parent: &cloudresourcemanager.ResourceId{Type: "folder", Id: strings.TrimPrefix(name, foldersPrefix)},
+			})
+			if err != nil {
+				c.recordCollectionError("", "project_hierarchy", err)
+				continue
+			}
+			c.folderHierarchy[name] = h
+		}
+	}
+
 	return nil
 }
 
+// ancestors returns the organizations and folders above all audited projects
+func (c *Client) ancestors() []string {
+	seen := map[string]bool{}
+	ancestors := []string{}
+	for _, h := range c.hierarchy {
+		for _, a := range h.Ancestors {
+			if !seen[a] {
+				seen[a] = true
+				ancestors = append(ancestors, a)
+			}
+		}
+	}
+	sort.Strings(ancestors)
+	return ancestors
+}
+
+// getAncestorIamPolicy retrieves the IAM policy of an organization or folder
+func (c *Client) getAncestorIamPolicy(name string) (*gcp.IamPolicyResource, error) {
+	if strings.HasPrefix(name, organizationsPrefix) {
+		policy, err := c.cloudResourceClient.Organizations.GetIamPolicy(name, &cloudresourcemanager.GetIamPolicyRequest{}).Do()
+		if err != nil {
+			return nil, err
+		}
+		return gcp.NewIamPolicyResource(policy), nil
+	}
+
+	policy, err := c.folderClient.Folders.GetIamPolicy(name, &cloudresourcemanagerv2.GetIamPolicyRequest{}).Do()
+	if err != nil {
+		return nil, err
+	}
+	return gcp.NewIamPolicyResourceFromFolder(policy), nil
+}
+
+// effectiveIamPolicy returns a project's IAM policy merged with the policies it inherits from its organization and folders
+func (c *Client) effectiveIamPolicy(projectID string, policy *gcp.IamPolicyResource) *gcp.IamPolicyResource {
+	policies := []*gcp.IamPolicyResource{}
+	if h, ok := c.hierarchy[projectID]; ok {
+		for _, a := range h.Ancestors {
+			policies = append(policies, c.folderPolicies[a])
+		}
+	}
+	return gcp.MergeIamPolicyResources(append(policies, policy)...)
+}
+
 type iamCallResult struct {
 	ProjectID       string
 	Policy          *gcp.IamPolicyResource
@@ -87,13 +165,31 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 	reports = []report.Report{}
 	typ := "iam_policy"
 
+	// Organization and folder policies are audited on their own, as their bindings apply to every project beneath them
+	for _, name := range c.ancestors() {
+		policy, ok := c.folderPolicies[name]
+		if !ok {
+			continue
+		}
+		r := c.generateAncestorIAMPolicyReport(typ, name, policy)
+		reports = append(reports, r)
+		c.incrementMetrics(&r)
+	}
+
 	for _, p := range c.computeprojects {
 		projectID := p.Name()
-		policy := c.policies[projectID]
 		serviceAccounts := c.serviceaccounts[projectID]
 
-		r := report.NewReport(
+		// Policies that could not be collected are reported as collection errors
+		policy, ok := c.policies[projectID]
+		if !ok {
+			continue
+		}
+
+		r := c.newReport(
 			typ,
+			projectID,
+			projectID,
 			fmt.Sprintf("Project %v IAM Policy", projectID),
 		)
 		r.Data, err = policy.Marshal()
@@ -101,12 +197,15 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 			glog.Fatalf("Failed to marshal IAM policy: %v", err)
 		}
 
+		// Bindings and audit configs are inherited from the organization and folders above the project
+		effective := c.effectiveIamPolicy(projectID, policy)
+
 		// Corporate login credentials should be used
 		corpCreds := report.NewCISControl(
 			"1.1",
 			fmt.Sprintf("Project %v should only allow corporate login credentials", p.Name()),
 		)
-		if err := policy.PolicyViolatesUserDomainWhitelist(); err != nil {
+		if err := policy.PolicyViolatesUserDomainWhitelist(c.cfg.IAM.UserDomains); err != nil {
 			corpCreds.Error = err.Error()
 		} else {
 			corpCreds.Passed()
@@ -122,6 +221,8 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 			)
 			if sa.HasUserManagedKeys() {
 				saManagedKeys.Error = "Service account has user-managed keys"
+			} else if sa.KeysError != nil {
+				saManagedKeys.Error = sa.KeysError.Error()
 			} else {
 				saManagedKeys.Passed()
 			}
@@ -132,7 +233,7 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 				"1.4",
 				fmt.Sprintf("%v should not have admin roles", sa.Email()),
 			)
-			if err := policy.MemberHasAdminRole(fmt.Sprintf("serviceAccount:%v", sa.Email())); err != nil {
+			if err := effective.MemberHasAdminRole(fmt.Sprintf("serviceAccount:%v", sa.Email())); err != nil {
 				saAdminRole.Error = err.Error()
 			} else {
 				saAdminRole.Passed()
@@ -146,7 +247,7 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 			"1.5",
 			fmt.Sprintf("Project %v should not allow project-wide use of Service Account User role", p.Name()),
 		)
-		if err := policy.PolicyAllowsIAMUserServiceAccountUserRole(); err != nil {
+		if err := effective.PolicyAllowsIAMUserServiceAccountUserRole(); err != nil {
 			saServiceAccountUserRole.Error = err.Error()
 		} else {
 			saServiceAccountUserRole.Passed()
@@ -159,8 +260,10 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 				"1.6",
 				fmt.Sprintf("%v should not have expired keys", sa.Email()),
 			)
-			if err := sa.HasKeysNeedingRotation(); err != nil {
+			if err := sa.HasKeysNeedingRotation(c.cfg.IAM.SAKeyExpirationTime); err != nil {
 				saKeyExpired.Error = err.Error()
+			} else if sa.KeysError != nil {
+				saKeyExpired.Error = sa.KeysError.Error()
 			} else {
 				saKeyExpired.Passed()
 			}
@@ -196,10 +299,10 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 			"2.1",
 			fmt.Sprintf("Project %v should proper audit logging configurations", p.Name()),
 		)
-		if err := policy.PolicyConfiguresAuditLogging(); err != nil {
+		if err := effective.PolicyConfiguresAuditLogging(); err != nil {
 			auditConfig.Error = err.Error()
 		} else {
-			if err := policy.PolicyDoesNotHaveAuditLogExceptions(); err != nil {
+			if err := effective.PolicyDoesNotHaveAuditLogExceptions(); err != nil {
 				auditConfig.Error = err.Error()
 			} else {
 				auditConfig.Passed()
@@ -212,3 +315,94 @@ func (c *Client) GenerateIAMPolicyReports() (reports []report.Report, err error)
 
 	return
 }
+
+// generateAncestorIAMPolicyReport runs the IAM policy controls that apply outside of a project against an organization or folder policy
+func (c *Client) generateAncestorIAMPolicyReport(typ string, name string, policy *gcp.IamPolicyResource) report.Report {
+
+	isOrganization := strings.HasPrefix(name, organizationsPrefix)
+
+	var r report.Report
+	if isOrganization {
+		r = c.newReport(typ, "", name, fmt.Sprintf("Organization %v IAM Policy", strings.TrimPrefix(name, organizationsPrefix)))
+		r.OrganizationID = strings.TrimPrefix(name, organizationsPrefix)
+	} else {
+		displayName := name
+		if f, ok := c.folders[name]; ok {
+			displayName = f.DisplayName
+		}
+		r = c.newReport(typ, "", name, fmt.Sprintf("Folder %v (%v) IAM Policy", displayName, name))
+		if h, ok := c.folderHierarchy[name]; ok {
+			r.OrganizationID = h.OrganizationID
+			r.FolderPath = h.FolderPath
+		}
+	}
+	r.ResourceName = "//cloudresourcemanager.googleapis.com/" + name
+	r.Location = gcp.GlobalLocation
+
+	var err error
+	if r.Data, err = policy.Marshal(); err != nil {
+		glog.Fatalf("Failed to marshal IAM policy: %v", err)
+	}
+
+	// Corporate login credentials should be used
+	corpCreds := report.NewCISControl(
+		"1.1",
+		fmt.Sprintf("%v should only allow corporate login credentials", name),
+	)
+	if err := policy.PolicyViolatesUserDomainWhitelist(c.cfg.IAM.UserDomains); err != nil {
+		corpCreds.Error = err.Error()
+	} else {
+		corpCreds.Passed()
+	}
+
+	// IAM Users should not be able to impersonate service accounts in every project beneath
+	saServiceAccountUserRole := report.NewCISControl(
+		"1.5",
+		fmt.Sprintf("%v should not allow use of Service Account User role", name),
+	)
+	if err := policy.PolicyAllowsIAMUserServiceAccountUserRole(); err != nil {
+		saServiceAccountUserRole.Error = err.Error()
+	} else {
+		saServiceAccountUserRole.Passed()
+	}
+
+	// Users should not be allowed to administrate and impersonate service accounts
+	saSeperateDuties := report.NewCISControl(
+		"1.7",
+		fmt.Sprintf("%v should have separation of duties with respect to service account usage", name),
+	)
+	if err := policy.PolicyViolatesServiceAccountSeparationoOfDuties(); err != nil {
+		saSeperateDuties.Error = err.Error()
+	} else {
+		saSeperateDuties.Passed()
+	}
+
+	// Users should not be allowed to administrate and utilize KMS functionality
+	kmsSeperateDuties := report.NewCISControl(
+		"1.9",
+		fmt.Sprintf("%v should have separation of duties with respect to KMS usage", name),
+	)
+	if err := policy.PolicyViolatesKMSSeparationoOfDuties(); err != nil {
+		kmsSeperateDuties.Error = err.Error()
+	} else {
+		kmsSeperateDuties.Passed()
+	}
+
+	// Audit logging is expected at the organization level. Folders may configure it, but otherwise inherit it
+	auditConfig := report.NewCISControl(
+		"2.1",
+		fmt.Sprintf("%v should have proper audit logging configurations", name),
+	)
+	if !isOrganization && !policy.HasAuditConfigs() {
+		auditConfig.NotApplicable("Folder does not configure audit logging, and inherits it from its parent")
+	} else if err := policy.PolicyConfiguresAuditLogging(); err != nil {
+		auditConfig.Error = err.Error()
+	} else if err := policy.PolicyDoesNotHaveAuditLogExceptions(); err != nil {
+		auditConfig.Error = err.Error()
+	} else {
+		auditConfig.Passed()
+	}
+
+	r.AddControls(corpCreds, saServiceAccountUserRole, saSeperateDuties, kmsSeperateDuties, auditConfig)
+	return r
+}
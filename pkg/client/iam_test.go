@@ -1 +1,89 @@
 package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/config"
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/stretchr/testify/assert"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
+	compute "google.golang.org/api/compute/v1"
+	iam "google.golang.org/api/iam/v1"
+)
+
+func TestEffectiveIamPolicy(t *testing.T) {
+
+	c := &Client{
+		hierarchy: map[string]*projectHierarchy{
+			"my-project": {OrganizationID: "123", Ancestors: []string{"organizations/123", "folders/1"}},
+		},
+		folderPolicies: map[string]*gcp.IamPolicyResource{
+			"folders/1": gcp.NewIamPolicyResource(&cloudresourcemanager.Policy{
+				Bindings: []*cloudresourcemanager.Binding{
+					{Role: "roles/iam.serviceAccountUser", Members: []string{"user:alice@example.com"}},
+				},
+			}),
+		},
+	}
+
+	// The service account user role granted on the folder is inherited by the project
+	project := gcp.NewIamPolicyResource(&cloudresourcemanager.Policy{})
+	assert.Nil(t, project.PolicyAllowsIAMUserServiceAccountUserRole())
+	assert.NotNil(t, c.effectiveIamPolicy("my-project", project).PolicyAllowsIAMUserServiceAccountUserRole())
+
+	// Projects outside of an organization only have their own policy
+	assert.Nil(t, c.effectiveIamPolicy("other-project", project).PolicyAllowsIAMUserServiceAccountUserRole())
+}
+
+func TestGenerateAncestorIAMPolicyReport(t *testing.T) {
+
+	c := &Client{
+		cfg: config.Default(),
+		folders: map[string]*cloudresourcemanagerv2.Folder{
+			"folders/1": {Name: "folders/1", DisplayName: "Engineering", Parent: "organizations/123"},
+		},
+		folderHierarchy: map[string]*projectHierarchy{
+			"folders/1": {OrganizationID: "123", FolderPath: "Engineering", Ancestors: []string{"organizations/123", "folders/1"}},
+		},
+	}
+
+	// Folders inherit audit logging from their parent when they do not configure it
+	r := c.generateAncestorIAMPolicyReport("iam_policy", "folders/1", gcp.NewIamPolicyResource(&cloudresourcemanager.Policy{}))
+	assert.Equal(t, "Folder Engineering (folders/1) IAM Policy", r.Title)
+	assert.Equal(t, "123", r.OrganizationID)
+	assert.Equal(t, "Engineering", r.FolderPath)
+	assert.Equal(t, report.Passed, r.Status())
+
+	// Organizations must configure audit logging
+	r = c.generateAncestorIAMPolicyReport("iam_policy", "organizations/123", gcp.NewIamPolicyResource(&cloudresourcemanager.Policy{}))
+	assert.Equal(t, "Organization 123 IAM Policy", r.Title)
+	assert.Equal(t, report.Failed, r.Status())
+}
+
+func TestGenerateIAMPolicyReportsKeysError(t *testing.T) {
+
+	// A service account whose keys could not be listed
+	sa := gcp.NewIamServiceAccountResource(&iam.ServiceAccount{Email: "sa@my-project.iam.gserviceaccount.com"})
+	sa.KeysError = errors.New("Error retrieving keys of service account sa@my-project.iam.gserviceaccount.com: googleapi: Error 403: Access denied")
+
+	c := &Client{
+		cfg:             config.Default(),
+		computeprojects: []*gcp.ComputeProjectResource{gcp.NewComputeProjectResource(&compute.Project{Name: "my-project"})},
+		policies:        map[string]*gcp.IamPolicyResource{"my-project": gcp.NewIamPolicyResource(&cloudresourcemanager.Policy{})},
+		serviceaccounts: map[string][]*gcp.IamServiceAccountResource{"my-project": {sa}},
+	}
+
+	reports, err := c.GenerateIAMPolicyReports()
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+
+	// The account is still reported, and its key controls fail with the collection error
+	assert.Equal(t, report.Failed, reports[0].Controls[1].Status)
+	assert.Equal(t, sa.KeysError.Error(), reports[0].Controls[1].Error)
+	assert.Equal(t, report.Passed, reports[0].Controls[2].Status)
+	assert.Equal(t, report.Failed, reports[0].Controls[4].Status)
+	assert.Equal(t, sa.KeysError.Error(), reports[0].Controls[4].Error)
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/UnityTech/nemesis/pkg/utils"
+	"github.com/golang/glog"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// GetKMSResources launches the process retrieving KMS key rings and crypto keys across all locations
+func (c *Client) GetKMSResources() error {
+
+	defer utils.Elapsed("GetKMSResources")()
+
+	worker := func(projectIDs <-chan string, results chan<- kmsCallResult) {
+
+		id := <-projectIDs
+		res := kmsCallResult{ProjectID: id, KeyRings: []*gcp.KMSKeyRingResource{}, CryptoKeys: []*gcp.KMSCryptoKeyResource{}}
+
+		// Check that the KMS API is enabled. If not, don't audit KMS resources in the project
+		if !c.isServiceEnabled(id, "cloudkms.googleapis.com") {
+			results <- res
+			return
+		}
+
+		ctx := context.Background()
+		locationsService := c.kmsClient.Projects.Locations
+		keyRingsService := c.kmsClient.Projects.Locations.KeyRings
+		cryptoKeysService := c.kmsClient.Projects.Locations.KeyRings.CryptoKeys
+
+		// Key rings are regional, so we must look for them in every location KMS is offered in
+		locations := []*cloudkms.Location{}
+		err := locationsService.List(fmt.Sprintf("projects/%v", id)).Pages(ctx, func(page *cloudkms.ListLocationsResponse) error {
+			locations = append(locations, page.Locations...)
+			return nil
+		})
+		if err != nil {
+			c.recordCollectionError(id, "kms_crypto_key", fmt.Errorf("Error retrieving KMS locations: %v", err))
+			results <- res
+			return
+		}
+
+		for _, l := range locations {
+
+			keyRings := []*cloudkms.KeyRing{}
+			err := keyRingsService.List(l.Name).Pages(ctx, func(page *cloudkms.ListKeyRingsResponse) error {
+				keyRings = append(keyRings, page.KeyRings...)
+				return nil
+			})
+			if err != nil {
+				c.recordCollectionError(id, "kms_crypto_key", fmt.Errorf("Error retrieving KMS key rings in location %v: %v", l.LocationId, err))
+				continue
+			}
+
+			for _, k := range keyRings {
+
+				keyRing := gcp.NewKMSKeyRingResource(k)
+				policy, err := keyRingsService.GetIamPolicy(k.Name).Do()
+				if err != nil {
+					c.recordCollectionError(id, "kms_crypto_key", fmt.Errorf("Error retrieving IAM policy for KMS key ring %v: %v", k.Name, err))
+					continue
+				}
+				keyRing.Policy = gcp.NewIamPolicyResourceFromKMS(policy)
+				res.KeyRings = append(res.KeyRings, keyRing)
+
+				cryptoKeys := []*cloudkms.CryptoKey{}
+				err = cryptoKeysService.List(k.Name).Pages(ctx, func(page *cloudkms.ListCryptoKeysResponse) error {
+					cryptoKeys = append(cryptoKeys, page.CryptoKeys...)
+					return nil
+				})
+				if err != nil {
+					c.recordCollectionError(id, "kms_crypto_key", fmt.Errorf("Error retrieving KMS crypto keys for key ring %v: %v", k.Name, err))
+					continue
+				}
+
+				for _, ck := range cryptoKeys {
+
+					cryptoKey := gcp.NewKMSCryptoKeyResource(ck, keyRing)
+					policy, err := cryptoKeysService.GetIamPolicy(ck.Name).Do()
+					if err != nil {
+						c.recordCollectionError(id, "kms_crypto_key", fmt.Errorf("Error retrieving IAM policy for KMS crypto key %v: %v", ck.Name, err))
+						continue
+					}
+					cryptoKey.Policy = gcp.NewIamPolicyResourceFromKMS(policy)
+					res.CryptoKeys = append(res.CryptoKeys, cryptoKey)
+				}
+			}
+		}
+
+		results <- res
+	}
+
+	// Setup worker pool
+	projectIDs := make(chan string, len(c.resourceprojects))
+	results := make(chan kmsCallResult, len(c.resourceprojects))
+	numWorkers := len(c.resourceprojects)
+	for w := 0; w < numWorkers; w++ {
+		go worker(projectIDs, results)
+	}
+
+	// Feed the workers and collect the KMS info
+	for _, p := range c.resourceprojects {
+		projectIDs <- p.ProjectId
+	}
+
+	// Collect the info
+	for i := 0; i < numWorkers; i++ {
+		res := <-results
+		c.keyRings[res.ProjectID] = res.KeyRings
+		c.cryptoKeys[res.ProjectID] = res.CryptoKeys
+	}
+
+	return nil
+}
+
+type kmsCallResult struct {
+	ProjectID  string
+	KeyRings   []*gcp.KMSKeyRingResource
+	CryptoKeys []*gcp.KMSCryptoKeyResource
+}
+
+// GenerateKMSCryptoKeyReports signals the client to process KMSCryptoKeyResource's for reports.
+// If there are no crypto keys found in the configuration, no reports will be created.
+func (c *Client) GenerateKMSCryptoKeyReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "kms_crypto_key"
+
+	for _, p := range c.resourceprojects {
+		projectID := p.ProjectId
+
+		for _, k := range c.cryptoKeys[projectID] {
+			keyRing := k.KeyRing()
+			r := c.newReport(
+				typ,
+				projectID,
+				k.Name(),
+				fmt.Sprintf("Project %v KMS Key Ring %v (%v) Crypto Key %v", projectID, keyRing.Name(), keyRing.Location(), k.Name()),
+			)
+			r.ResourceName = k.ResourceName()
+			r.Location = keyRing.Location()
+			c.labelReport(&r, k.Labels())
+			if r.Data, err = k.Marshal(); err != nil {
+				glog.Fatalf("Failed to marshal KMS crypto key: %v", err)
+			}
+
+			// Keys should be rotated on a regular interval
+			rotation := report.NewCISControl(
+				"1.8",
+				fmt.Sprintf("Crypto key %v should be rotated on a regular interval", k.Name()),
+			)
+			if !k.IsSymmetric() {
+				rotation.NotApplicable("Only symmetric crypto keys support automatic rotation")
+			} else if err := k.IsRotatedWithinPeriod(c.cfg.KMS.KeyRotationTime); err != nil {
+				rotation.Error = err.Error()
+			} else {
+				rotation.Passed()
+			}
+
+			// Users should not be allowed to administrate and utilize the key, including via the key ring
+			separateDuties := report.NewCISControl(
+				"1.9",
+				fmt.Sprintf("Crypto key %v should have separation of duties with respect to KMS usage", k.Name()),
+			)
+			if err := k.EffectivePolicy().PolicyViolatesKMSSeparationoOfDuties(); err != nil {
+				separateDuties.Error = err.Error()
+			} else {
+				separateDuties.Passed()
+			}
+
+			// Keys should never be usable by anyone on the internet
+			public := report.NewControl(
+				"kmsKeyNotPublic",
+				fmt.Sprintf("Crypto key %v should not be accessible by allUsers or allAuthenticatedUsers", k.Name()),
+			)
+			if err := k.EffectivePolicy().PolicyAllowsPublicAccess(); err != nil {
+				public.Error = err.Error()
+			} else {
+				public.Passed()
+			}
+
+			r.AddControls(rotation, separateDuties, public)
+
+			reports = append(reports, r)
+			c.incrementMetrics(&r)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"github.com/UnityTech/nemesis/pkg/config"
+	"github.com/UnityTech/nemesis/pkg/report"
+)
+
+// profile is a control selection that applies to resources with matching labels
+type profile struct {
+	name     string
+	labels   map[string]string
+	selector *report.Selector
+}
+
+// newProfiles returns the policy profiles of the configuration, in order of precedence
+func newProfiles(cfgs []config.ProfileConfig) ([]*profile, error) {
+	profiles := []*profile{}
+	for _, cfg := range cfgs {
+		selector, err := report.NewSelector(cfg.Controls.Include, cfg.Controls.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, &profile{name: cfg.Name, labels: cfg.Labels, selector: selector})
+	}
+	return profiles, nil
+}
+
+// matches returns whether a set of labels includes every label of the profile
+func (p *profile) matches(labels map[string]string) bool {
+	for k, v := range p.labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// labelReport adds the labels of a resource to its report, and selects the report's controls with the first
+// policy profile matching the labels. Without a matching profile, the configured control selection is used
+func (c *Client) labelReport(r *report.Report, labels map[string]string) {
+	for k, v := range labels {
+		r.Labels[k] = v
+	}
+
+	r.Profile = ""
+	r.SelectControls(c.selector)
+	for _, p := range c.profiles {
+		if p.matches(r.Labels) {
+			r.Profile = p.name
+			r.SelectControls(p.selector)
+			return
+		}
+	}
+}
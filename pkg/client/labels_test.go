@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/config"
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelReport(t *testing.T) {
+
+	profiles, err := newProfiles([]config.ProfileConfig{
+		{Name: "production", Labels: map[string]string{"env": "prod"}},
+		{Name: "sandbox", Labels: map[string]string{"env": "sandbox"}, Controls: config.ControlsConfig{Include: []string{"level1"}}},
+	})
+	assert.Nil(t, err)
+
+	selector, err := report.NewSelector(nil, []string{"level2"})
+	assert.Nil(t, err)
+
+	c := &Client{
+		selector:      selector,
+		profiles:      profiles,
+		projectLabels: map[string]map[string]string{"my-project": {"team": "platform", "env": "sandbox"}},
+	}
+
+	// Resource labels take precedence over the labels of the project
+	r := c.newReport("compute_instance", "my-project", "my-instance", "Project my-project Compute Instance my-instance")
+	c.labelReport(&r, map[string]string{"env": "prod"})
+	assert.Equal(t, map[string]string{"team": "platform", "env": "prod"}, r.Labels)
+	assert.Equal(t, "production", r.Profile)
+
+	// The production profile evaluates level 2 controls that are excluded by default
+	r.AddControls(report.NewCISControl("4.6", "Disks should be encrypted with CSEK"))
+	assert.Equal(t, report.Failed, r.Controls[0].Status)
+
+	r = c.newReport("compute_instance", "my-project", "my-instance", "Project my-project Compute Instance my-instance")
+	assert.Equal(t, "sandbox", r.Profile)
+	r.AddControls(report.NewCISControl("4.6", "Disks should be encrypted with CSEK"))
+	assert.Equal(t, report.Skipped, r.Controls[0].Status)
+
+	// Without a matching profile, the configured selection is used
+	r = c.newReport("compute_instance", "other-project", "my-instance", "Project other-project Compute Instance my-instance")
+	assert.Equal(t, "", r.Profile)
+	r.AddControls(report.NewCISControl("4.5", "IP forwarding should be disabled"), report.NewCISControl("4.6", "Disks should be encrypted with CSEK"))
+	assert.Equal(t, report.Failed, r.Controls[0].Status)
+	assert.Equal(t, report.Skipped, r.Controls[1].Status)
+}
@@ -33,8 +33,12 @@ func (c *Client) GetLoggingResources() error {
 		}
 		it1 := c.logConfigClient.ListSinks(ctx, &req1)
 		for {
-			s, done := it1.Next()
-			if done == iterator.Done {
+			s, err := it1.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				c.recordCollectionError(id, "logging_sink", err)
 				break
 			}
 
@@ -47,8 +51,12 @@ func (c *Client) GetLoggingResources() error {
 		}
 		it2 := c.logMetricClient.ListLogMetrics(ctx, &req2)
 		for {
-			m, done := it2.Next()
-			if done == iterator.Done {
+			m, err := it2.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				c.recordCollectionError(id, "logging_metric", err)
 				break
 			}
 
@@ -84,16 +92,29 @@ type loggingClientResult struct {
 	LogMetrics []*gcp.LoggingMetricResource
 }
 
+// findBucket returns the storage bucket with the given name from any of the audited projects
+func (c *Client) findBucket(name string) *gcp.StorageBucketResource {
+	for _, buckets := range c.buckets {
+		for _, b := range buckets {
+			if b.Name() == name {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
 // GenerateLoggingReports signals the client to process LoggingResources for reports.
-// TODO - implement CIS 2.3
 func (c *Client) GenerateLoggingReports() (reports []report.Report, err error) {
 
 	reports = []report.Report{}
 
 	for _, p := range c.computeprojects {
 
-		r := report.NewReport(
+		r := c.newReport(
 			"logging_configuration",
+			p.Name(),
+			p.Name(),
 			fmt.Sprintf("Project %s Logging Configuration", p.Name()),
 		)
 
@@ -115,6 +136,55 @@ func (c *Client) GenerateLoggingReports() (reports []report.Report, err error) {
 			exportLogs.Passed()
 		}
 
+		// Buckets that logs are exported to should have object versioning and retention policies
+		bucketControls := []report.Control{}
+		for _, s := range c.logSinks[p.Name()] {
+			dest, err := s.Destination()
+			if err != nil || dest.Type != gcp.LoggingSinkDestinationStorage {
+				continue
+			}
+
+			versioning := report.NewCISControl(
+				"2.3",
+				fmt.Sprintf("Bucket %s exported to by sink %s should have object versioning enabled", dest.Name, s.Name()),
+			)
+			retention := report.NewControl(
+				"logBucketRetentionPolicy",
+				fmt.Sprintf("Bucket %s exported to by sink %s should have a locked retention policy", dest.Name, s.Name()),
+			)
+
+			bucket := c.findBucket(dest.Name)
+			if bucket == nil {
+				notAudited := fmt.Sprintf("Sink %s exports to bucket %s, which is not in an audited project", s.Name(), dest.Name)
+				versioning.Error = notAudited
+				retention.Error = notAudited
+			} else {
+				if bucket.IsVersioningEnabled() {
+					versioning.Passed()
+				} else {
+					versioning.Error = fmt.Sprintf("Bucket %s does not have object versioning enabled", dest.Name)
+				}
+
+				if err := bucket.HasRetentionPolicy(); err != nil {
+					retention.Error = err.Error()
+				} else {
+					retention.Passed()
+				}
+			}
+
+			bucketControls = append(bucketControls, versioning, retention)
+		}
+
+		// If no sink exports to a bucket, there are no log buckets to check
+		if len(bucketControls) == 0 {
+			versioning := report.NewCISControl(
+				"2.3",
+				fmt.Sprintf("Project %s log buckets should have object versioning enabled", p.Name()),
+			)
+			versioning.NotApplicable(fmt.Sprintf("Project %s has no logging sinks that export to a storage bucket", p.Name()))
+			bucketControls = append(bucketControls, versioning)
+		}
+
 		// Helper function to determine if a list of log-based metrics contains a specific filter
 		metricExists := func(metrics []*gcp.LoggingMetricResource, filter string) bool {
 			for _, m := range metrics {
@@ -230,6 +300,7 @@ func (c *Client) GenerateLoggingReports() (reports []report.Report, err error) {
 			gcsIamChanges,
 			sqlConfigChanges,
 		)
+		r.AddControls(bucketControls...)
 		reports = append(reports, r)
 	}
 
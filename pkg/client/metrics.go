@@ -4,6 +4,10 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/UnityTech/nemesis/pkg/config"
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/golang/glog"
+
 	"github.com/prometheus/client_golang/prometheus"
 	push "github.com/prometheus/client_golang/prometheus/push"
 )
@@ -29,14 +33,32 @@ var (
 		},
 		[]string{"type", "name", "status", "project"},
 	)
+	// Collection errors, reported by resource type and project
+	collectionErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: promNamespace,
+			Name:      "collection_errors",
+			Help:      "Failures to collect resources by resource type and project",
+		},
+		[]string{"resource_type", "project"},
+	)
+	// Waived controls, reported by type, control, waiver status and project
+	waivedControlsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: promNamespace,
+			Name:      "waived_controls",
+			Help:      "Controls covered by a waiver by type, control, waiver status (waived or expired), and project",
+		},
+		[]string{"type", "control", "status", "project"},
+	)
 )
 
 // configureMetrics is a helper function for configuring metrics.
 // Since we use a push gateway, we must configure our metrics as a push model
-func configureMetrics() *push.Pusher {
+func configureMetrics(cfg config.MetricsConfig) *push.Pusher {
 
 	// Only configure metrics collection if enabled
-	if *flagMetricsEnabled {
+	if cfg.Enabled {
 
 		// Create the prometheus registry. We explicitly declare a registry rather than
 		// depend on the default registry
@@ -45,9 +67,11 @@ func configureMetrics() *push.Pusher {
 		// Register the necessary metrics
 		registry.MustRegister(totalResourcesCounter)
 		registry.MustRegister(reportSummary)
+		registry.MustRegister(collectionErrorsCounter)
+		registry.MustRegister(waivedControlsCounter)
 
 		// Configure the gateway and return the pusher
-		pusher := push.New(*flagMetricsGateway, "nemesis_audit").Gatherer(registry)
+		pusher := push.New(cfg.Gateway, "nemesis_audit").Gatherer(registry)
 		return pusher
 	}
 
@@ -55,9 +79,19 @@ func configureMetrics() *push.Pusher {
 }
 
 // incrementMetrics is a small helper to consolidate reporting metrics that are reported for all resources
-func (c *Client) incrementMetrics(typ string, name string, status string, projectID string) {
+func (c *Client) incrementMetrics(r *report.Report) {
 	totalResourcesCounter.Inc()
-	reportSummary.WithLabelValues(typ, name, status, projectID).Inc()
+	reportSummary.WithLabelValues(r.Type, r.Resource, r.Status(), r.ProjectID).Inc()
+
+	// Waived controls, and controls whose waiver expired, are counted separately from the report status
+	for _, control := range r.Controls {
+		if control.Status == report.Waived {
+			waivedControlsCounter.WithLabelValues(r.Type, control.Waiver.Control, report.Waived, r.ProjectID).Inc()
+		} else if control.HasExpiredWaiver() {
+			waivedControlsCounter.WithLabelValues(r.Type, control.Waiver.Control, "expired", r.ProjectID).Inc()
+			glog.Warningf("Waiver for control %v of %v owned by %v expired on %v", control.Waiver.Control, r.Title, control.Waiver.Owner, control.Waiver.Expires)
+		}
+	}
 }
 
 // PushMetrics pushes the collected metrics from this client. Should only be called once.
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/UnityTech/nemesis/pkg/report"
@@ -18,11 +19,12 @@ func (c *Client) GetNetworkResources() error {
 
 	regionNames, err := c.getRegionNames()
 	if err != nil {
-		glog.Fatalf("%v", err)
+		c.recordCollectionError("", "compute_subnetwork", err)
 	}
 
 	worker := func(projectIDs <-chan string, results chan<- networkCallResult) {
 		id := <-projectIDs
+		ctx := context.Background()
 
 		res := networkCallResult{
 			ProjectID:   id,
@@ -32,75 +34,53 @@ func (c *Client) GetNetworkResources() error {
 			Addresses:   []*gcp.ComputeAddressResource{},
 		}
 
-		// Get all networks active in the project
-		networks, err := c.computeClient.Networks.List(id).Do()
-		if err != nil {
-			glog.Fatalf("Error retrieving networks from project '%v': %v", id, err)
+		// Network resources are only available when the compute API is enabled
+		if !c.isServiceEnabled(id, "compute.googleapis.com") {
+			results <- res
+			return
 		}
 
-		for _, n := range networks.Items {
-			res.Networks = append(res.Networks, gcp.NewComputeNetworkResource(n))
+		// Get all networks active in the project
+		if err := c.computeClient.Networks.List(id).Pages(ctx, func(networks *compute.NetworkList) error {
+			for _, n := range networks.Items {
+				res.Networks = append(res.Networks, gcp.NewComputeNetworkResource(n))
+			}
+			return nil
+		}); err != nil {
+			c.recordCollectionError(id, "compute_network", err)
 		}
 
 		// Get all subnetworks active in the project
 		for _, region := range regionNames {
-			var subnetworks *compute.SubnetworkList
-
-			subnetworks, err = c.computeClient.Subnetworks.List(id, region).Do()
-			if err != nil {
-				glog.Fatalf("Error retrieving subnetworks from project '%v': %v", id, err)
-			}
-
-			for _, s := range subnetworks.Items {
-				res.Subnetworks = append(res.Subnetworks, gcp.NewComputeSubnetworkResource(s))
-			}
-
-			for subnetworks.NextPageToken != "" {
-				subnetworks, err := c.computeClient.Subnetworks.List(id, region).PageToken(subnetworks.NextPageToken).Do()
-				if err != nil {
-					glog.Fatalf("Error retrieving subnetworks from project '%v': %v", id, err)
-				}
-
+			if err := c.computeClient.Subnetworks.List(id, region).Pages(ctx, func(subnetworks *compute.SubnetworkList) error {
 				for _, s := range subnetworks.Items {
 					res.Subnetworks = append(res.Subnetworks, gcp.NewComputeSubnetworkResource(s))
 				}
+				return nil
+			}); err != nil {
+				c.recordCollectionError(id, "compute_subnetwork", fmt.Errorf("Error retrieving subnetworks in region %v: %v", region, err))
 			}
 		}
 
 		// Get all firewall rules active in audited projects, for all networks in the projects
-
-		var firewalls *compute.FirewallList
-
-		firewalls, err = c.computeClient.Firewalls.List(id).Do()
-		if err != nil {
-			glog.Fatalf("Error retrieving firewall rules from project '%v': %v", id, err)
-		}
-
-		for _, f := range firewalls.Items {
-			res.Firewalls = append(res.Firewalls, gcp.NewComputeFirewallRuleResource(f))
-		}
-
-		for firewalls.NextPageToken != "" {
-			firewalls, err = c.computeClient.Firewalls.List(id).PageToken(firewalls.NextPageToken).Do()
-			if err != nil {
-				glog.Fatalf("Error retrieving firewall rules from project '%v': %v", id, err)
-			}
-
+		if err := c.computeClient.Firewalls.List(id).Pages(ctx, func(firewalls *compute.FirewallList) error {
 			for _, f := range firewalls.Items {
 				res.Firewalls = append(res.Firewalls, gcp.NewComputeFirewallRuleResource(f))
 			}
+			return nil
+		}); err != nil {
+			c.recordCollectionError(id, "compute_firewall_rule", err)
 		}
 
 		// Get aggregated IPs for the projects
 		aggregateAddressesList, err := c.computeClient.Addresses.AggregatedList(id).Do()
 		if err != nil {
-			glog.Fatalf("Error retrieving addresses from project '%v': %v", id, err)
-		}
-
-		scopedAddresses := aggregateAddressesList.Items
-		for _, scope := range scopedAddresses {
-			for _, a := range scope.Addresses {
-				res.Addresses = append(res.Addresses, gcp.NewComputeAddressResource(a))
+			c.recordCollectionError(id, "compute_address", err)
+		} else {
+			for _, scope := range aggregateAddressesList.Items {
+				for _, a := range scope.Addresses {
+					res.Addresses = append(res.Addresses, gcp.NewComputeAddressResource(a))
+				}
 			}
 		}
 
@@ -151,10 +131,13 @@ func (c *Client) GenerateComputeNetworkReports() (reports []report.Report, err e
 		projectID := p.Name()
 
 		for _, n := range c.networks[p.Name()] {
-			r := report.NewReport(
+			r := c.newReport(
 				typ,
+				projectID,
+				n.Name(),
 				fmt.Sprintf("Network %v in Project %v", n.Name(), p.Name()),
 			)
+			r.ResourceName = n.ResourceName()
 			r.Data, err = n.Marshal()
 			if err != nil {
 				glog.Fatalf("Failed to marshal network: %v", err)
@@ -185,7 +168,7 @@ func (c *Client) GenerateComputeNetworkReports() (reports []report.Report, err e
 			r.AddControls(defaultNetworkControl, legacyNetworkControl)
 
 			reports = append(reports, r)
-			c.incrementMetrics(typ, n.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 	}
 
@@ -203,10 +186,14 @@ func (c *Client) GenerateComputeSubnetworkReports() (reports []report.Report, er
 		projectID := p.Name()
 
 		for _, s := range c.subnetworks[p.Name()] {
-			r := report.NewReport(
+			r := c.newReport(
 				typ,
+				projectID,
+				s.Name(),
 				fmt.Sprintf("Subnetwork %v in region %v for Project %v", s.Name(), s.Region(), p.Name()),
 			)
+			r.ResourceName = s.ResourceName()
+			r.Location = s.Location()
 			r.Data, err = s.Marshal()
 			if err != nil {
 				glog.Fatalf("Failed to marshal subnetwork: %v", err)
@@ -235,13 +222,19 @@ func (c *Client) GenerateComputeSubnetworkReports() (reports []report.Report, er
 			r.AddControls(privateAccessControl, flowLogsControl)
 
 			reports = append(reports, r)
-			c.incrementMetrics(typ, s.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 	}
 
 	return
 }
 
+// firewallExposesPort returns whether a firewall rule exposes a port to the internet, taking into account
+// deny rules of a higher priority within the same project
+func (c *Client) firewallExposesPort(projectID string, f *gcp.ComputeFirewallRuleResource, protocol string, port int) bool {
+	return f.ExposesPortToInternet(protocol, port) && !f.IsOverriddenBy(c.firewalls[projectID], protocol, port)
+}
+
 // GenerateComputeFirewallRuleReports signals the client to process ComputeFirewallRuleResource's for reports.
 // If there are no network keys configured in the configuration, no reports will be created.
 func (c *Client) GenerateComputeFirewallRuleReports() (reports []report.Report, err error) {
@@ -253,10 +246,13 @@ func (c *Client) GenerateComputeFirewallRuleReports() (reports []report.Report,
 		projectID := p.Name()
 
 		for _, f := range c.firewalls[p.Name()] {
-			r := report.NewReport(
+			r := c.newReport(
 				typ,
+				projectID,
+				f.Name(),
 				fmt.Sprintf("Network %v Firewall Rule %v", f.Network(), f.Name()),
 			)
+			r.ResourceName = f.ResourceName()
 			r.Data, err = f.Marshal()
 			if err != nil {
 				glog.Fatalf("Failed to marshal firewall rule: %v", err)
@@ -267,7 +263,7 @@ func (c *Client) GenerateComputeFirewallRuleReports() (reports []report.Report,
 				"3.6",
 				"SSH should not be allowed from the internet",
 			)
-			if (f.AllowsProtocolPort("TCP", "22") || f.AllowsProtocolPort("UDP", "22")) && f.AllowsSourceRange("0.0.0.0/0") {
+			if c.firewallExposesPort(projectID, f, "tcp", 22) || c.firewallExposesPort(projectID, f, "udp", 22) {
 				sshControl.Error = fmt.Sprintf("%v allows SSH from the internet", f.Name())
 			} else {
 				sshControl.Passed()
@@ -278,15 +274,29 @@ func (c *Client) GenerateComputeFirewallRuleReports() (reports []report.Report,
 				"3.7",
 				"RDP should not be allowed from the internet",
 			)
-			if (f.AllowsProtocolPort("TCP", "3389") || f.AllowsProtocolPort("UDP", "3389")) && f.AllowsSourceRange("0.0.0.0/0") {
-				rdpControl.Error = fmt.Sprintf("%v allows RDP froom the internet", f.Name())
+			if c.firewallExposesPort(projectID, f, "tcp", 3389) || c.firewallExposesPort(projectID, f, "udp", 3389) {
+				rdpControl.Error = fmt.Sprintf("%v allows RDP from the internet", f.Name())
 			} else {
 				rdpControl.Passed()
 			}
 
 			r.AddControls(sshControl, rdpControl)
+
+			// Each configured sensitive service should not be reachable from the internet
+			for _, sp := range c.cfg.Compute.Firewall.SensitivePorts {
+				sensitivePortControl := report.NewControl(
+					fmt.Sprintf("sensitivePort=%v", sp),
+					fmt.Sprintf("%v (%v/%v) should not be allowed from the internet", sp.Name, sp.Protocol, sp.Port),
+				)
+				if c.firewallExposesPort(projectID, f, sp.Protocol, sp.Port) {
+					sensitivePortControl.Error = fmt.Sprintf("%v allows %v from the internet", f.Name(), sp.Name)
+				} else {
+					sensitivePortControl.Passed()
+				}
+				r.AddControls(sensitivePortControl)
+			}
 			reports = append(reports, r)
-			c.incrementMetrics(typ, f.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 	}
 
@@ -305,17 +315,21 @@ func (c *Client) GenerateComputeAddressReports() (reports []report.Report, err e
 		projectID := p.Name()
 		for _, a := range c.addresses[projectID] {
 
-			r := report.NewReport(
+			r := c.newReport(
 				typ,
+				projectID,
+				a.Name(),
 				fmt.Sprintf("Compute Address %v", a.Name()),
 			)
+			r.ResourceName = a.ResourceName()
+			r.Location = a.Location()
 			r.Data, err = a.Marshal()
 			if err != nil {
 				glog.Fatalf("Failed to marshal compute address: %v", err)
 			}
 
 			reports = append(reports, r)
-			c.incrementMetrics(typ, a.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 	}
 
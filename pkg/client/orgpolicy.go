@@ -0,0 +1,147 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/UnityTech/nemesis/pkg/utils"
+	"github.com/golang/glog"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+var (
+	// Organization policy constraints that guarantee compliance with a CIS recommendation when enforced. Constraints that
+	// are not retroactive, such as iam.disableServiceAccountKeyCreation or sql.restrictPublicIp, leave existing resources
+	// non-compliant and are not listed
+	orgPolicyCISReferences = map[string]string{
+		"constraints/compute.requireOsLogin": "4.3",
+	}
+)
+
+// orgPolicyConstraints returns the configured list of organization policy constraints that should be enforced
+func (c *Client) orgPolicyConstraints() []string {
+	constraints := []string{}
+	for _, constraint := range c.cfg.OrgPolicy.Constraints {
+		constraints = append(constraints, gcp.OrgPolicyConstraintName(constraint))
+	}
+	return constraints
+}
+
+// GetOrgPolicyResources launches the process retrieving the effective organization policies of each project
+func (c *Client) GetOrgPolicyResources() error {
+
+	defer utils.Elapsed("GetOrgPolicyResources")()
+
+	constraints := c.orgPolicyConstraints()
+
+	worker := func(projectIDs <-chan string, results chan<- orgPolicyCallResult) {
+
+		id := <-projectIDs
+		res := orgPolicyCallResult{ProjectID: id, Policies: []*gcp.OrgPolicyResource{}}
+
+		for _, constraint := range constraints {
+			req := cloudresourcemanager.GetEffectiveOrgPolicyRequest{Constraint: constraint}
+			policy, err := c.cloudResourceClient.Projects.GetEffectiveOrgPolicy(fmt.Sprintf("projects/%v", id), &req).Do()
+			if err != nil {
+				c.recordCollectionError(id, "org_policy", fmt.Errorf("Error retrieving effective organization policy for %v: %v", constraint, err))
+				continue
+			}
+
+			// The API omits the constraint when no policy is set on the project or its ancestors
+			if policy.Constraint == "" {
+				policy.Constraint = constraint
+			}
+			res.Policies = append(res.Policies, gcp.NewOrgPolicyResource(policy))
+		}
+
+		results <- res
+	}
+
+	// Setup worker pool
+	projectIDs := make(chan string, len(c.resourceprojects))
+	results := make(chan orgPolicyCallResult, len(c.resourceprojects))
+	numWorkers := len(c.resourceprojects)
+	for w := 0; w < numWorkers; w++ {
+		go worker(projectIDs, results)
+	}
+
+	// Feed the workers and collect the organization policy info
+	for _, p := range c.resourceprojects {
+		projectIDs <- p.ProjectId
+	}
+
+	// Collect the info
+	for i := 0; i < numWorkers; i++ {
+		res := <-results
+		c.orgPolicies[res.ProjectID] = res.Policies
+	}
+
+	return nil
+}
+
+type orgPolicyCallResult struct {
+	ProjectID string
+	Policies  []*gcp.OrgPolicyResource
+}
+
+// orgPolicyEnforced returns whether an organization policy constraint is enforced on a project
+func (c *Client) orgPolicyEnforced(projectID string, constraint string) bool {
+	constraint = gcp.OrgPolicyConstraintName(constraint)
+	for _, p := range c.orgPolicies[projectID] {
+		if p.Constraint() == constraint {
+			return p.IsEnforced() == nil
+		}
+	}
+	return false
+}
+
+// passIfOrgPolicyEnforced marks a failed control as passed when an organization policy constraint guarantees compliance
+func (c *Client) passIfOrgPolicyEnforced(control *report.Control, projectID string, constraint string) {
+	if control.Status == report.Failed && c.orgPolicyEnforced(projectID, constraint) {
+		control.PassedBy(fmt.Sprintf("Enforced by organization policy %v", gcp.OrgPolicyConstraintName(constraint)))
+	}
+}
+
+// GenerateOrgPolicyReports signals the client to process OrgPolicyResource's for reports.
+// If no constraints are configured, no reports will be created.
+func (c *Client) GenerateOrgPolicyReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "org_policy"
+
+	for _, p := range c.resourceprojects {
+
+		projectID := p.ProjectId
+
+		for _, policy := range c.orgPolicies[projectID] {
+			r := c.newReport(
+				typ,
+				projectID,
+				policy.Constraint(),
+				fmt.Sprintf("Project %v Organization Policy %v", projectID, policy.Constraint()),
+			)
+			if r.Data, err = policy.Marshal(); err != nil {
+				glog.Fatalf("Failed to marshal organization policy: %v", err)
+			}
+
+			desc := fmt.Sprintf("Organization policy %v should be enforced", policy.Constraint())
+			if cisID, ok := orgPolicyCISReferences[policy.Constraint()]; ok {
+				desc = fmt.Sprintf("%v to support CIS %v", desc, cisID)
+			}
+
+			enforced := report.NewControl(fmt.Sprintf("orgPolicyEnforced=%v", policy.Constraint()), desc)
+			if err := policy.IsEnforced(); err != nil {
+				enforced.Error = err.Error()
+			} else {
+				enforced.Passed()
+			}
+
+			r.AddControls(enforced)
+			reports = append(reports, r)
+			c.incrementMetrics(&r)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/stretchr/testify/assert"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestPassIfOrgPolicyEnforced(t *testing.T) {
+
+	c := &Client{
+		orgPolicies: map[string][]*gcp.OrgPolicyResource{
+			"enforced-project": {gcp.NewOrgPolicyResource(&cloudresourcemanager.OrgPolicy{
+				Constraint:    "constraints/compute.requireOsLogin",
+				BooleanPolicy: &cloudresourcemanager.BooleanPolicy{Enforced: true},
+			})},
+			"unenforced-project": {gcp.NewOrgPolicyResource(&cloudresourcemanager.OrgPolicy{
+				Constraint:    "constraints/compute.requireOsLogin",
+				BooleanPolicy: &cloudresourcemanager.BooleanPolicy{},
+			})},
+		},
+	}
+
+	control := report.NewCISControl("4.3", "OS login should be enabled")
+	c.passIfOrgPolicyEnforced(&control, "enforced-project", "compute.requireOsLogin")
+	assert.Equal(t, report.Passed, control.Status)
+	assert.Equal(t, "Enforced by organization policy constraints/compute.requireOsLogin", control.Detail)
+
+	control = report.NewCISControl("4.3", "OS login should be enabled")
+	c.passIfOrgPolicyEnforced(&control, "unenforced-project", "compute.requireOsLogin")
+	assert.Equal(t, report.Failed, control.Status)
+
+	control = report.NewCISControl("4.3", "OS login should be enabled")
+	c.passIfOrgPolicyEnforced(&control, "other-project", "compute.requireOsLogin")
+	assert.Equal(t, report.Failed, control.Status)
+}
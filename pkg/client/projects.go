@@ -1,30 +1,66 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/UnityTech/nemesis/pkg/resource/gcp"
 	"github.com/UnityTech/nemesis/pkg/utils"
-	"github.com/golang/glog"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	serviceusage "google.golang.org/api/serviceusage/v1"
 )
 
 // GetProjects gathers the list of projects and active API resources for the project
 func (c *Client) GetProjects() error {
 
-	if *flagProjectFilter == "" {
-		glog.Exitf("No project filter was provided. Either specify --project.filter or set NEMESIS_PROJECT_FILTER to the appropriate regex (e.g. my-cool-projects-*)")
-	}
-
 	defer utils.Elapsed("GetProjects")()
 
-	// Get list of all projects.
+	excludes := parseExcludes(c.cfg.Projects.Exclude)
+
+	// Get list of all projects, either beneath the configured scope or matching the filter.
 	// Additionally we must make sure that the project is ACTIVE. Any other state will return errors
-	projectFilter := fmt.Sprintf("%v AND lifecycleState=ACTIVE", *flagProjectFilter)
-	projects := listAllProjects(projectFilter, c.cloudResourceClient)
+	var projects []*cloudresourcemanager.Project
+	var err error
+	source := fmt.Sprintf("%v AND lifecycleState=ACTIVE", c.cfg.Projects.Filter)
+	if c.cfg.Projects.Scope != "" {
+		source = c.cfg.Projects.Scope
+		projects, err = c.listScopeProjects(c.cfg.Projects.Scope, excludes)
+	} else {
+		projects, err = listAllProjects(source, c.cloudResourceClient)
+	}
+	if err != nil {
+		c.recordCollectionError("", "project", err)
+		return err
+	}
+
+	// Resolve where each project resides in the resource hierarchy, and drop projects that are excluded directly or
+	// through one of their folders. Projects listed by filter are not pruned while walking a scope, so folder
+	// excludes are checked here as well
+	included := []*cloudresourcemanager.Project{}
+	for _, p := range projects {
+		if excludes[projectsPrefix+p.ProjectId] {
+			continue
+		}
+
+		h, err := c.resolveHierarchy(p)
+		if err != nil {
+			c.recordCollectionError(p.ProjectId, "project_hierarchy", err)
+		} else if h.isExcluded(excludes) {
+			continue
+		} else {
+			c.hierarchy[p.ProjectId] = h
+		}
+
+		included = append(included, p)
+		c.projectLabels[p.ProjectId] = p.Labels
+		c.projectNumbers[p.ProjectId] = strconv.FormatInt(p.ProjectNumber, 10)
+	}
+	projects = included
 
 	// Return an error that we retrieved no projects
 	if len(projects) == 0 {
-		return fmt.Errorf("No projects found when matching against '%v'", projectFilter)
+		return fmt.Errorf("No projects found when matching against '%v'", source)
 	}
 
 	// Create a short-lived goroutine for retrieving project services
@@ -32,14 +68,16 @@ func (c *Client) GetProjects() error {
 		id := <-projectIDs
 		projectID := fmt.Sprintf("projects/%v", id)
 
-		servicesList, err := c.serviceusageClient.Services.List(projectID).Filter("state:ENABLED").Do()
-		if err != nil {
-			glog.Fatalf("Failed to retrieve list of services for project %v: %v", projectID, err)
-		}
-
+		// Without the list of enabled services, resources that depend on a specific API are not audited
 		projectServices := []*gcp.ServiceAPIResource{}
-		for _, s := range servicesList.Services {
-			projectServices = append(projectServices, gcp.NewServiceAPIResource(s))
+		err := c.serviceusageClient.Services.List(projectID).Filter("state:ENABLED").Pages(context.Background(), func(page *serviceusage.ListServicesResponse) error {
+			for _, s := range page.Services {
+				projectServices = append(projectServices, gcp.NewServiceAPIResource(s))
+			}
+			return nil
+		})
+		if err != nil {
+			c.recordCollectionError(id, "service", err)
 		}
 
 		res := serviceCallResult{ProjectID: id, Services: projectServices}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/golang/glog"
+)
+
+// newRunID returns a random identifier for a scan, formatted as a version 4 UUID, so that every report of a scan
+// can be traced back to it
+func newRunID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		glog.Fatalf("Failed to generate scan run ID: %v", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
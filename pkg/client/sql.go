@@ -0,0 +1,177 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/UnityTech/nemesis/pkg/utils"
+	"github.com/golang/glog"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+)
+
+// GetSQLResources launches the process retrieving Cloud SQL instances and their users
+func (c *Client) GetSQLResources() error {
+
+	defer utils.Elapsed("GetSQLResources")()
+
+	worker := func(projectIDs <-chan string, results chan<- sqlCallResult) {
+
+		id := <-projectIDs
+		res := sqlCallResult{ProjectID: id, Instances: []*gcp.SqlInstanceResource{}}
+
+		// Check that the Cloud SQL API is enabled. If not, don't audit SQL resources in the project
+		if !c.isServiceEnabled(id, "sqladmin.googleapis.com") {
+			results <- res
+			return
+		}
+
+		var instances *sqladmin.InstancesListResponse
+
+		instances, err := c.sqlClient.Instances.List(id).Do()
+		if err != nil {
+			c.recordCollectionError(id, "sql_instance", err)
+			results <- res
+			return
+		}
+
+		dbInstances := instances.Items
+		for instances.NextPageToken != "" {
+			instances, err = c.sqlClient.Instances.List(id).PageToken(instances.NextPageToken).Do()
+			if err != nil {
+				c.recordCollectionError(id, "sql_instance", err)
+				break
+			}
+			dbInstances = append(dbInstances, instances.Items...)
+		}
+
+		for _, i := range dbInstances {
+
+			instance := gcp.NewSqlInstanceResource(i)
+
+			// Users can only be listed from instances that are running
+			if instance.IsRunnable() {
+				users, err := c.sqlClient.Users.List(id, i.Name).Do()
+				if err != nil {
+					c.recordCollectionError(id, "sql_instance", fmt.Errorf("Error retrieving users for Cloud SQL instance %v: %v", i.Name, err))
+					continue
+				}
+				instance.Users = append(instance.Users, users.Items...)
+			}
+
+			res.Instances = append(res.Instances, instance)
+		}
+
+		results <- res
+	}
+
+	// Setup worker pool
+	projectIDs := make(chan string, len(c.resourceprojects))
+	results := make(chan sqlCallResult, len(c.resourceprojects))
+	numWorkers := len(c.resourceprojects)
+	for w := 0; w < numWorkers; w++ {
+		go worker(projectIDs, results)
+	}
+
+	// Feed the workers and collect the SQL info
+	for _, p := range c.resourceprojects {
+		projectIDs <- p.ProjectId
+	}
+
+	// Collect the info
+	for i := 0; i < numWorkers; i++ {
+		res := <-results
+		c.sqlInstances[res.ProjectID] = res.Instances
+	}
+
+	return nil
+}
+
+type sqlCallResult struct {
+	ProjectID string
+	Instances []*gcp.SqlInstanceResource
+}
+
+// GenerateSQLInstanceReports signals the client to process SqlInstanceResource's for reports.
+// If there are no Cloud SQL instances found in the configuration, no reports will be created.
+func (c *Client) GenerateSQLInstanceReports() (reports []report.Report, err error) {
+
+	reports = []report.Report{}
+	typ := "sql_instance"
+
+	for _, p := range c.resourceprojects {
+		projectID := p.ProjectId
+
+		for _, i := range c.sqlInstances[projectID] {
+			r := c.newReport(
+				typ,
+				projectID,
+				i.Name(),
+				fmt.Sprintf("Project %v Cloud SQL Instance %v", projectID, i.Name()),
+			)
+			r.ResourceName = i.ResourceName()
+			r.Location = i.Region()
+			c.labelReport(&r, i.Labels())
+			if r.Data, err = i.Marshal(); err != nil {
+				glog.Fatalf("Failed to marshal Cloud SQL instance: %v", err)
+			}
+
+			// Instances should only accept SSL connections
+			sslControl := report.NewCISControl(
+				"6.1",
+				fmt.Sprintf("Cloud SQL instance %v should require all incoming connections to use SSL", i.Name()),
+			)
+			if i.RequiresSSL() {
+				sslControl.Passed()
+			} else {
+				sslControl.Error = "Cloud SQL instance does not require SSL for incoming connections"
+			}
+
+			// Instances should not authorize connections from the entire internet
+			worldControl := report.NewCISControl(
+				"6.2",
+				fmt.Sprintf("Cloud SQL instance %v should not be open to the world", i.Name()),
+			)
+			if err := i.IsOpenToTheWorld(); err != nil {
+				worldControl.Error = err.Error()
+			} else {
+				worldControl.Passed()
+			}
+
+			r.AddControls(sslControl, worldControl)
+
+			// User host restrictions only apply to MySQL instances
+			if i.IsMySQL() {
+
+				// Administrative users should not be able to connect from any host
+				adminControl := report.NewCISControl(
+					"6.3",
+					fmt.Sprintf("Cloud SQL instance %v should not allow anyone to connect with administrative privileges", i.Name()),
+				)
+				if err := i.AllowsUsersFromAnyHost(); err != nil {
+					adminControl.Error = err.Error()
+				} else {
+					adminControl.Passed()
+				}
+
+				// The root user should not be able to connect from any host
+				rootControl := report.NewCISControl(
+					"6.4",
+					fmt.Sprintf("Cloud SQL instance %v should not allow root login from any host", i.Name()),
+				)
+				if err := i.AllowsRootFromAnyHost(); err != nil {
+					rootControl.Error = err.Error()
+				} else {
+					rootControl.Passed()
+				}
+
+				r.AddControls(adminControl, rootControl)
+			}
+
+			reports = append(reports, r)
+			c.incrementMetrics(&r)
+		}
+	}
+
+	return
+}
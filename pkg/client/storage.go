@@ -22,35 +22,61 @@ func (c *Client) GetStorageResources() error {
 		// Get the project's buckets
 		bucketList, err := c.storageClient.Buckets.List(id).Do()
 		if err != nil {
-			glog.Fatalf("Error retrieving project %v's bucket list: %v", id, err)
+			c.recordCollectionError(id, "storage_bucket", err)
+			results <- res
+			return
 		}
 
 		for _, b := range bucketList.Items {
 
-			// Get the ACLs for the bucket, as they are not included by default in the bucket list call
-			acls, err := c.storageClient.BucketAccessControls.List(b.Name).Do()
+			bucket := gcp.NewStorageBucketResource(b)
+
+			// Get the IAM policy for the bucket, as public access can be granted through IAM as well as ACLs.
+			// The bucket is still audited when access controls cannot be read, with the controls that depend on them not applicable
+			policy, err := c.storageClient.Buckets.GetIamPolicy(b.Name).Do()
 			if err != nil {
+				bucket.PolicyError = fmt.Errorf("Error retrieving bucket %v's IAM policy: %v", b.Name, err)
+				c.recordCollectionError(id, "storage_bucket", bucket.PolicyError)
+			} else {
+				bucket.Policy = gcp.NewIamPolicyResourceFromStorage(policy)
+			}
 
-				continue
-				/*
-					// The call above will throw a 400 error if Bucket Policy Only is enabled
-					if strings.Contains(
-						err.Error(),
-						"googleapi: Error 400: Cannot get legacy ACLs for a bucket that has enabled Bucket Policy Only",
-					) {
-						continue
+			// Legacy ACLs cannot be read when Bucket Policy Only is enabled, as IAM is the only form of access control
+			bucketPolicyOnly, _ := bucket.HasBucketPolicyOnlyEnabled()
+			if !bucketPolicyOnly {
+
+				// Get the ACLs for the bucket, as they are not included by default in the bucket list call
+				acls, err := c.storageClient.BucketAccessControls.List(b.Name).Do()
+				if err != nil {
+					bucket.ACLError = fmt.Errorf("Error retrieving bucket %v's ACLs: %v", b.Name, err)
+					c.recordCollectionError(id, "storage_bucket", bucket.ACLError)
+				} else {
+					// Store the ACLs with the bucket
+					b.Acl = acls.Items
+				}
+
+				// Optionally sample the default object ACL and objects to look for publicly accessible objects
+				if c.cfg.Storage.ObjectACLSampleSize > 0 {
+					defaultACLs, err := c.storageClient.DefaultObjectAccessControls.List(b.Name).Do()
+					if err != nil {
+						bucket.ObjectsError = fmt.Errorf("Error retrieving bucket %v's default object ACLs: %v", b.Name, err)
+						c.recordCollectionError(id, "storage_bucket", bucket.ObjectsError)
+					} else {
+						b.DefaultObjectAcl = defaultACLs.Items
 					}
-				*/
 
-				// Otherwise, we hit a real error
-				//glog.Fatalf("Error retrieving bucket %v's ACLs: %v", b.Name, err)
+					objects, err := c.storageClient.Objects.List(b.Name).Projection("full").MaxResults(int64(c.cfg.Storage.ObjectACLSampleSize)).Do()
+					if err != nil {
+						bucket.ObjectsError = fmt.Errorf("Error retrieving bucket %v's objects: %v", b.Name, err)
+						c.recordCollectionError(id, "storage_bucket", bucket.ObjectsError)
+					} else {
+						bucket.SampledObjects = objects.Items
+					}
+				}
 			}
 
-			// Store the ACLs with the bucket
-			b.Acl = acls.Items
-
 			// Append a new bucket resource
-			res.Buckets = append(res.Buckets, gcp.NewStorageBucketResource(b))
+			res.Buckets = append(res.Buckets, bucket)
 		}
 
 		results <- res
@@ -96,39 +122,92 @@ func (c *Client) GenerateStorageBucketReports() (reports []report.Report, err er
 		projectBuckets := c.buckets[projectID]
 
 		for _, b := range projectBuckets {
-			r := report.NewReport(typ, fmt.Sprintf("Project %v Storage Bucket %v", projectID, b.Name()))
+			r := c.newReport(typ, projectID, b.Name(), fmt.Sprintf("Project %v Storage Bucket %v", projectID, b.Name()))
+			r.ResourceName = b.ResourceName()
+			r.Location = b.Location()
+			c.labelReport(&r, b.Labels())
 			if r.Data, err = b.Marshal(); err != nil {
 				glog.Fatalf("Failed to marshal storage bucket: %v", err)
 			}
 
 			allUsersControl := report.NewCISControl(
 				"5.1",
-				"Bucket ACL should not include entity 'allUsers'",
+				"Bucket ACL and IAM policy should not include entity 'allUsers'",
 			)
 
-			if !b.AllowAllUsers() {
-				allUsersControl.Passed()
-			} else {
+			if b.AllowAllUsers() {
 				allUsersControl.Error = "Bucket ACL includes entity 'allUsers'"
+			} else if b.PolicyAllowsMember("allUsers") {
+				allUsersControl.Error = "Bucket IAM policy includes member 'allUsers'"
+			} else if err := b.AccessControlsError(); err != nil {
+				allUsersControl.Error = err.Error()
+			} else {
+				allUsersControl.Passed()
 			}
 
 			// Add the `allAuthenticatedUsers` entity control if the spec says that allAuthenticatedUsers == false
 			allAuthenticatedUsersControl := report.NewCISControl(
 				"5.1",
-				"Bucket ACL should not include entity 'allAuthenticatedUsers'",
+				"Bucket ACL and IAM policy should not include entity 'allAuthenticatedUsers'",
 			)
 
-			if !b.AllowAllAuthenticatedUsers() {
+			if b.AllowAllAuthenticatedUsers() {
+				allAuthenticatedUsersControl.Error = "Bucket ACL includes entity 'allAuthenticatedUsers'"
+			} else if b.PolicyAllowsMember("allAuthenticatedUsers") {
+				allAuthenticatedUsersControl.Error = "Bucket IAM policy includes member 'allAuthenticatedUsers'"
+			} else if err := b.AccessControlsError(); err != nil {
+				allAuthenticatedUsersControl.Error = err.Error()
+			} else {
 				allAuthenticatedUsersControl.Passed()
+			}
+
+			// Objects should not be publicly accessible. This is only checked when objects are sampled
+			publicObjectsControl := report.NewCISControl(
+				"5.2",
+				"Bucket should not contain publicly accessible objects",
+			)
+			bucketPolicyOnly, err := b.HasBucketPolicyOnlyEnabled()
+			if bucketPolicyOnly {
+				publicObjectsControl.NotApplicable("Bucket Policy Only is enabled, so object access is governed by the bucket IAM policy")
+			} else if c.cfg.Storage.ObjectACLSampleSize <= 0 {
+				publicObjectsControl.NotApplicable("Object ACL sampling is disabled")
+			} else if err := b.HasPublicObjects(); err != nil {
+				publicObjectsControl.Error = err.Error()
+			} else if b.ObjectsError != nil {
+				publicObjectsControl.Error = b.ObjectsError.Error()
 			} else {
-				allAuthenticatedUsersControl.Error = "Bucket ACL includes entity 'allAuthenticatedUsers'"
+				publicObjectsControl.Passed()
+			}
+
+			// Buckets should deliver access logs to a log bucket
+			loggingControl := report.NewCISControl(
+				"5.3",
+				"Bucket should have access logging enabled",
+			)
+			if b.IsLoggingEnabled() {
+				loggingControl.Passed()
+			} else {
+				loggingControl.Error = "Bucket does not have access logging enabled"
+			}
+
+			// Buckets should use IAM only, rather than a mix of IAM and legacy ACLs
+			bucketPolicyOnlyControl := report.NewControl(
+				"bucketPolicyOnly",
+				"Bucket should have Bucket Policy Only enabled",
+			)
+			if err != nil {
+				bucketPolicyOnlyControl.Error = err.Error()
+			} else if bucketPolicyOnly {
+				bucketPolicyOnlyControl.Passed()
+			} else {
+				bucketPolicyOnlyControl.Error = "Bucket does not have Bucket Policy Only enabled"
 			}
 
-			r.AddControls(allUsersControl, allAuthenticatedUsersControl)
+			r.AddControls(allUsersControl, allAuthenticatedUsersControl, publicObjectsControl, loggingControl, bucketPolicyOnlyControl)
 
 			// Add the bucket report to the final list of bucket reports
 			reports = append(reports, r)
-			c.incrementMetrics(typ, b.Name(), r.Status(), projectID)
+			c.incrementMetrics(&r)
 		}
 
 	}
@@ -1 +1,49 @@
 package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/config"
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/UnityTech/nemesis/pkg/resource/gcp"
+	"github.com/stretchr/testify/assert"
+	compute "google.golang.org/api/compute/v1"
+	storage "google.golang.org/api/storage/v1"
+)
+
+func TestGenerateStorageBucketReportsPartialCollection(t *testing.T) {
+
+	// A public bucket whose object sample could not be listed
+	bucket := gcp.NewStorageBucketResource(&storage.Bucket{
+		Name: "public-bucket",
+		Acl:  []*storage.BucketAccessControl{{Entity: "allUsers", Role: "READER"}},
+	})
+	bucket.ObjectsError = errors.New("Error retrieving bucket public-bucket's objects: googleapi: Error 403: Access denied")
+
+	// A bucket whose IAM policy could not be read
+	private := gcp.NewStorageBucketResource(&storage.Bucket{Name: "private-bucket"})
+	private.PolicyError = errors.New("Error retrieving bucket private-bucket's IAM policy: googleapi: Error 403: Access denied")
+
+	cfg := config.Default()
+	cfg.Storage.ObjectACLSampleSize = 10
+	c := &Client{
+		cfg:             cfg,
+		computeprojects: []*gcp.ComputeProjectResource{gcp.NewComputeProjectResource(&compute.Project{Name: "my-project"})},
+		buckets:         map[string][]*gcp.StorageBucketResource{"my-project": {bucket, private}},
+	}
+
+	reports, err := c.GenerateStorageBucketReports()
+	assert.Nil(t, err)
+	assert.Len(t, reports, 2)
+
+	// The public bucket still fails 5.1, and the public objects control fails because it could not be evaluated
+	assert.Equal(t, report.Failed, reports[0].Controls[0].Status)
+	assert.Equal(t, report.Failed, reports[0].Controls[2].Status)
+	assert.Equal(t, bucket.ObjectsError.Error(), reports[0].Controls[2].Error)
+
+	// Without its IAM policy, the private bucket cannot be shown to pass 5.1
+	assert.Equal(t, report.Failed, reports[1].Controls[0].Status)
+	assert.Equal(t, report.Failed, reports[1].Controls[1].Status)
+	assert.Equal(t, private.PolicyError.Error(), reports[1].Controls[0].Error)
+}
@@ -1,34 +1,27 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"github.com/golang/glog"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 )
 
 // listAllProjects returns the list of all Projects visible to the authenticated client
-func listAllProjects(filter string, client *cloudresourcemanager.Service) []*cloudresourcemanager.Project {
+func listAllProjects(filter string, client *cloudresourcemanager.Service) ([]*cloudresourcemanager.Project, error) {
 
 	var projects []*cloudresourcemanager.Project
 
-	projectListCall, err := client.Projects.List().Filter(fmt.Sprintf("name:%v", filter)).Do()
+	err := client.Projects.List().Filter(fmt.Sprintf("name:%v", filter)).Pages(context.Background(), func(page *cloudresourcemanager.ListProjectsResponse) error {
+		projects = append(projects, page.Projects...)
+		return nil
+	})
 	if err != nil {
-		glog.Fatalf("Error retreiving projects: %v", err)
+		return nil, fmt.Errorf("Error retrieving projects: %v", err)
 	}
 
-	projects = append(projects, projectListCall.Projects...)
-
-	for projectListCall.NextPageToken != "" {
-		projectListCall, err := client.Projects.List().PageToken(projectListCall.NextPageToken).Do()
-		if err != nil {
-			glog.Fatalf("Error retreiving projects: %v", err)
-		}
-		projects = append(projects, projectListCall.Projects...)
-	}
-
-	return projects
+	return projects, nil
 }
 
 func (c *Client) isServiceEnabled(projectID, servicename string) bool {
@@ -0,0 +1,341 @@
+// Package config defines the configuration of an audit, loaded from a file, environment variables and flags
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	// Default Values
+	defaultOAuthMinimalScopes = []string{
+		"https://www.googleapis.com/auth/devstorage.read_only",
+		"https://www.googleapis.com/auth/logging.write",
+		"https://www.googleapis.com/auth/monitoring",
+		"https://www.googleapis.com/auth/servicecontrol",
+		"https://www.googleapis.com/auth/service.management.readonly",
+		"https://www.googleapis.com/auth/trace.append",
+	}
+	defaultSensitivePorts = []SensitivePort{
+		{Name: "mysql", Protocol: "tcp", Port: 3306},
+		{Name: "postgres", Protocol: "tcp", Port: 5432},
+		{Name: "redis", Protocol: "tcp", Port: 6379},
+		{Name: "elasticsearch", Protocol: "tcp", Port: 9200},
+		{Name: "kubernetes-api", Protocol: "tcp", Port: 6443},
+		{Name: "etcd", Protocol: "tcp", Port: 2379},
+		{Name: "mongodb", Protocol: "tcp", Port: 27017},
+	}
+	defaultOrgPolicyConstraints = []string{
+		"compute.vmExternalIpAccess",
+		"iam.disableServiceAccountKeyCreation",
+		"storage.uniformBucketLevelAccess",
+		"compute.requireOsLogin",
+		"sql.restrictPublicIp",
+	}
+)
+
+// Config is the configuration of an audit
+type Config struct {
+	Debug     bool             `yaml:"debug"`
+	Projects  ProjectsConfig   `yaml:"projects"`
+	Controls  ControlsConfig   `yaml:"controls"`
+	Profiles  []ProfileConfig  `yaml:"profiles"`
+	Waivers   WaiversConfig    `yaml:"waivers"`
+	Compute   ComputeConfig    `yaml:"compute"`
+	Container ContainerConfig  `yaml:"container"`
+	IAM       IAMConfig        `yaml:"iam"`
+	KMS       KMSConfig        `yaml:"kms"`
+	OrgPolicy OrgPolicyConfig  `yaml:"orgPolicy"`
+	Storage   StorageConfig    `yaml:"storage"`
+	Metrics   MetricsConfig    `yaml:"metrics"`
+	Reports   ReportsConfig    `yaml:"reports"`
+	Threshold report.Threshold `yaml:"threshold"`
+}
+
+// ProjectsConfig selects the projects to audit
+type ProjectsConfig struct {
+	Filter  string   `yaml:"filter"`
+	Scope   string   `yaml:"scope"`
+	Exclude []string `yaml:"exclude"`
+}
+
+// ControlsConfig selects the controls to evaluate. Terms are CIS IDs (4.6), CIS levels (level1),
+// scored or unscored, report types (compute_instance) or titles of controls that are not CIS controls
+type ControlsConfig struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+}
+
+// ProfileConfig replaces the control selection for resources whose labels, or the labels of their project,
+// include every label of the profile. The first matching profile is used
+type ProfileConfig struct {
+	Name     string            `yaml:"name"`
+	Labels   map[string]string `yaml:"labels"`
+	Controls ControlsConfig    `yaml:"controls"`
+}
+
+// WaiversConfig lists accepted risks. Waivers are read from the config file and from a separate waiver file
+type WaiversConfig struct {
+	File  string          `yaml:"file"`
+	Rules []report.Waiver `yaml:"rules"`
+}
+
+// ComputeConfig configures compute instance and firewall controls
+type ComputeConfig struct {
+	Instance InstanceConfig `yaml:"instance"`
+	Firewall FirewallConfig `yaml:"firewall"`
+}
+
+// InstanceConfig configures compute instance controls
+type InstanceConfig struct {
+	NumInterfaces     int  `yaml:"numInterfaces"`
+	AllowNat          bool `yaml:"allowNat"`
+	AllowIPForwarding bool `yaml:"allowIpForwarding"`
+}
+
+// FirewallConfig configures firewall rule controls
+type FirewallConfig struct {
+	SensitivePorts     []SensitivePort `yaml:"sensitivePorts"`
+	SensitivePortsFile string          `yaml:"sensitivePortsFile"`
+}
+
+// ContainerConfig configures GKE controls
+type ContainerConfig struct {
+	OAuthScopes []string `yaml:"oauthScopes"`
+}
+
+// IAMConfig configures IAM controls
+type IAMConfig struct {
+	UserDomains          []string `yaml:"userDomains"`
+	SAKeyExpirationTime  int      `yaml:"saKeyExpirationTime"`
+	APIKeyExpirationTime int      `yaml:"apiKeyExpirationTime"`
+}
+
+// KMSConfig configures Cloud KMS controls
+type KMSConfig struct {
+	KeyRotationTime int `yaml:"keyRotationTime"`
+}
+
+// OrgPolicyConfig configures organization policy controls
+type OrgPolicyConfig struct {
+	Constraints []string `yaml:"constraints"`
+}
+
+// StorageConfig configures Cloud Storage controls
+type StorageConfig struct {
+	ObjectACLSampleSize int `yaml:"objectAclSampleSize"`
+}
+
+// MetricsConfig configures the Prometheus push gateway
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Gateway string `yaml:"gateway"`
+}
+
+// ReportsConfig configures where reports are published
+type ReportsConfig struct {
+	OnlyFailures bool         `yaml:"onlyFailures"`
+	Stdout       StdoutConfig `yaml:"stdout"`
+	PubSub       PubSubConfig `yaml:"pubsub"`
+	Sarif        SarifConfig  `yaml:"sarif"`
+	JUnit        JUnitConfig  `yaml:"junit"`
+	HTML         HTMLConfig   `yaml:"html"`
+	CSV          CSVConfig    `yaml:"csv"`
+}
+
+// StdoutConfig configures the stdout reporter
+type StdoutConfig struct {
+	Enable bool   `yaml:"enable"`
+	Format string `yaml:"format"`
+}
+
+// PubSubConfig configures the Pub/Sub reporter
+type PubSubConfig struct {
+	Enable  bool   `yaml:"enable"`
+	Project string `yaml:"project"`
+	Topic   string `yaml:"topic"`
+}
+
+// SarifConfig configures the SARIF reporter, which is enabled by setting the path of the SARIF log file
+type SarifConfig struct {
+	Path string `yaml:"path"`
+}
+
+// JUnitConfig configures the JUnit XML reporter, which is enabled by setting the path of the JUnit XML file
+type JUnitConfig struct {
+	Path string `yaml:"path"`
+}
+
+// HTMLConfig configures the HTML reporter, which is enabled by setting the path of the HTML file
+type HTMLConfig struct {
+	Path string `yaml:"path"`
+}
+
+// CSVConfig configures the CSV reporter, which is enabled by setting the path of the CSV file, or "-" for stdout
+type CSVConfig struct {
+	Path    string   `yaml:"path"`
+	Columns []string `yaml:"columns"`
+}
+
+// Default returns the default configuration
+func Default() *Config {
+	c := new(Config)
+	c.Compute.Instance.NumInterfaces = 1
+	c.Compute.Firewall.SensitivePorts = append([]SensitivePort{}, defaultSensitivePorts...)
+	c.Container.OAuthScopes = append([]string{}, defaultOAuthMinimalScopes...)
+	c.IAM.SAKeyExpirationTime = 90
+	c.IAM.APIKeyExpirationTime = 90
+	c.KMS.KeyRotationTime = 365
+	c.OrgPolicy.Constraints = append([]string{}, defaultOrgPolicyConstraints...)
+	c.Metrics.Gateway = "127.0.0.1:9091"
+	c.Reports.Stdout.Format = report.FormatJSON
+	c.Reports.PubSub.Topic = "nemesis"
+	c.Reports.CSV.Columns = append([]string{}, report.DefaultCSVColumns...)
+	return c
+}
+
+// loadFile reads a YAML or JSON configuration file on top of the current configuration
+func (c *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read config file: %v", err)
+	}
+
+	// JSON is a subset of YAML, so both are read by the YAML decoder. Unknown keys are rejected to catch typos
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("Failed to parse config file %v: %v", path, err)
+	}
+	for i := range c.Compute.Firewall.SensitivePorts {
+		c.Compute.Firewall.SensitivePorts[i].Protocol = strings.ToLower(c.Compute.Firewall.SensitivePorts[i].Protocol)
+	}
+	return nil
+}
+
+// loadSensitivePortsFile reads a YAML or JSON list of sensitive ports, replacing the sensitive ports of the configuration
+func (c *Config) loadSensitivePortsFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read sensitive ports file: %v", err)
+	}
+
+	ports := []SensitivePort{}
+	if err := yaml.UnmarshalStrict(data, &ports); err != nil {
+		return fmt.Errorf("Failed to parse sensitive ports file %v: %v", path, err)
+	}
+	for i := range ports {
+		ports[i].Protocol = strings.ToLower(ports[i].Protocol)
+	}
+	c.Compute.Firewall.SensitivePorts = ports
+	return nil
+}
+
+// loadWaiversFile reads a YAML or JSON list of waivers, adding them to the waivers of the configuration
+func (c *Config) loadWaiversFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read waiver file: %v", err)
+	}
+
+	waivers := []report.Waiver{}
+	if err := yaml.UnmarshalStrict(data, &waivers); err != nil {
+		return fmt.Errorf("Failed to parse waiver file %v: %v", path, err)
+	}
+	c.Waivers.Rules = append(c.Waivers.Rules, waivers...)
+	return nil
+}
+
+// Validate returns an error describing every invalid setting in the configuration
+func (c *Config) Validate() error {
+
+	problems := []string{}
+
+	if c.Projects.Filter == "" && c.Projects.Scope == "" {
+		problems = append(problems, "either projects.filter or projects.scope must be set")
+	}
+	if c.Projects.Scope != "" && !strings.HasPrefix(c.Projects.Scope, "organizations/") && !strings.HasPrefix(c.Projects.Scope, "folders/") {
+		problems = append(problems, fmt.Sprintf("projects.scope '%v' must be formatted as organizations/<id> or folders/<id>", c.Projects.Scope))
+	}
+
+	if _, err := report.NewSelector(c.Controls.Include, c.Controls.Exclude); err != nil {
+		problems = append(problems, fmt.Sprintf("controls: %v", err))
+	}
+
+	for _, p := range c.Profiles {
+		if p.Name == "" || len(p.Labels) == 0 {
+			problems = append(problems, "profiles must have a name and at least one label")
+		}
+		if _, err := report.NewSelector(p.Controls.Include, p.Controls.Exclude); err != nil {
+			problems = append(problems, fmt.Sprintf("profile %v controls: %v", p.Name, err))
+		}
+	}
+
+	for _, w := range c.Waivers.Rules {
+		if err := w.Validate(); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if c.Compute.Instance.NumInterfaces < 1 {
+		problems = append(problems, "compute.instance.numInterfaces must be at least 1")
+	}
+	for _, p := range c.Compute.Firewall.SensitivePorts {
+		if err := p.Validate(); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if c.IAM.SAKeyExpirationTime < 1 {
+		problems = append(problems, "iam.saKeyExpirationTime must be at least 1 day")
+	}
+	if c.IAM.APIKeyExpirationTime < 1 {
+		problems = append(problems, "iam.apiKeyExpirationTime must be at least 1 day")
+	}
+	if c.KMS.KeyRotationTime < 1 {
+		problems = append(problems, "kms.keyRotationTime must be at least 1 day")
+	}
+	if c.Storage.ObjectACLSampleSize < 0 {
+		problems = append(problems, "storage.objectAclSampleSize must not be negative")
+	}
+
+	if c.Metrics.Enabled && c.Metrics.Gateway == "" {
+		problems = append(problems, "metrics.gateway must be set when metrics are enabled")
+	}
+	if c.Reports.PubSub.Enable && c.Reports.PubSub.Project == "" {
+		problems = append(problems, "reports.pubsub.project must be set when the Pub/Sub reporter is enabled")
+	}
+	if c.Reports.PubSub.Enable && c.Reports.PubSub.Topic == "" {
+		problems = append(problems, "reports.pubsub.topic must be set when the Pub/Sub reporter is enabled")
+	}
+	if !contains(report.StdOutFormats, c.Reports.Stdout.Format) {
+		problems = append(problems, fmt.Sprintf("reports.stdout.format must be one of %v", strings.Join(report.StdOutFormats, ", ")))
+	}
+	if c.Reports.CSV.Path == report.CSVStdout && c.Reports.Stdout.Enable {
+		problems = append(problems, fmt.Sprintf("reports.csv.path must not be %v when the stdout reporter is enabled", report.CSVStdout))
+	}
+	if err := report.ValidateCSVColumns(c.Reports.CSV.Columns); err != nil {
+		problems = append(problems, fmt.Sprintf("reports.csv.columns: %v", err))
+	}
+	if err := c.Threshold.Validate(); err != nil {
+		problems = append(problems, fmt.Sprintf("threshold: %v", err))
+	}
+
+	if len(problems) > 0 {
+		return errors.New("Invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// contains returns whether a list of strings includes the given value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
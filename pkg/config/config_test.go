@@ -0,0 +1,195 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/UnityTech/nemesis/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, name string, contents string) string {
+	dir, err := ioutil.TempDir("", "nemesis-config")
+	assert.Nil(t, err)
+	path := filepath.Join(dir, name)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+
+	cfg, err := Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--project.filter=my-project"})
+	assert.Nil(t, err)
+	assert.Equal(t, "my-project", cfg.Projects.Filter)
+	assert.Equal(t, 1, cfg.Compute.Instance.NumInterfaces)
+	assert.Equal(t, 365, cfg.KMS.KeyRotationTime)
+	assert.Equal(t, "nemesis", cfg.Reports.PubSub.Topic)
+	assert.Len(t, cfg.Compute.Firewall.SensitivePorts, 7)
+	assert.Len(t, cfg.Container.OAuthScopes, 6)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+
+	path := writeConfigFile(t, "nemesis.yaml", `
+projects:
+  filter: file-project
+iam:
+  userDomains: [example.com]
+  saKeyExpirationTime: 30
+kms:
+  keyRotationTime: 180
+compute:
+  firewall:
+    sensitivePorts:
+      - name: memcached
+        protocol: UDP
+        port: 11211
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	os.Setenv("NEMESIS_KMS_KEY_ROTATION_TIME", "90")
+	os.Setenv("NEMESIS_IAM_SA_KEY_EXPIRATION_TIME", "60")
+	defer os.Unsetenv("NEMESIS_KMS_KEY_ROTATION_TIME")
+	defer os.Unsetenv("NEMESIS_IAM_SA_KEY_EXPIRATION_TIME")
+
+	cfg, err := Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{
+		"--config", path,
+		"--iam.sa-key-expiration-time=45",
+		"--reports.stdout.enable",
+		"--threshold.enable",
+		"--threshold.level=1",
+		"--threshold.scored",
+	})
+	assert.Nil(t, err)
+
+	// Values only present in the file are kept
+	assert.Equal(t, "file-project", cfg.Projects.Filter)
+	assert.Equal(t, []string{"example.com"}, cfg.IAM.UserDomains)
+	assert.Equal(t, []SensitivePort{{Name: "memcached", Protocol: "udp", Port: 11211}}, cfg.Compute.Firewall.SensitivePorts)
+
+	// Environment variables override the file, and flags override both
+	assert.Equal(t, 90, cfg.KMS.KeyRotationTime)
+	assert.Equal(t, 45, cfg.IAM.SAKeyExpirationTime)
+	assert.True(t, cfg.Reports.Stdout.Enable)
+	assert.Equal(t, report.Threshold{Enable: true, Level: 1, Scored: true}, cfg.Threshold)
+}
+
+func TestLoadJSON(t *testing.T) {
+
+	path := writeConfigFile(t, "nemesis.json", `{"projects": {"scope": "organizations/123", "exclude": ["folders/456"]}}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	cfg, err := Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--config=" + path})
+	assert.Nil(t, err)
+	assert.Equal(t, "organizations/123", cfg.Projects.Scope)
+	assert.Equal(t, []string{"folders/456"}, cfg.Projects.Exclude)
+}
+
+func TestLoadWaivers(t *testing.T) {
+
+	path := writeConfigFile(t, "waivers.yaml", `
+- project: my-site
+  type: storage_bucket
+  resource: www-*
+  control: "5.1"
+  justification: Bucket serves the public website
+  owner: web-team@example.com
+  expires: 2030-01-31
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	cfg, err := Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--project.filter=my-site", "--waivers.file", path})
+	assert.Nil(t, err)
+	assert.Len(t, cfg.Waivers.Rules, 1)
+	assert.Equal(t, "www-*", cfg.Waivers.Rules[0].Resource)
+	assert.Equal(t, "2030-01-31", cfg.Waivers.Rules[0].Expires)
+
+	// Waivers without a justification, owner or expiry are rejected
+	path = writeConfigFile(t, "waivers.json", `[{"control": "5.1"}]`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	_, err = Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--project.filter=my-site", "--waivers.file", path})
+	assert.NotNil(t, err)
+}
+
+func TestLoadSensitivePortsFile(t *testing.T) {
+
+	path := writeConfigFile(t, "ports.json", `[{"name": "memcached", "protocol": "UDP", "port": 11211}]`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	// The file takes precedence over the list of sensitive ports
+	cfg, err := Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--project.filter=my-project", "--compute.firewall.sensitive-ports=redis:tcp/6379", "--compute.firewall.sensitive-ports-file", path})
+	assert.Nil(t, err)
+	assert.Equal(t, []SensitivePort{{Name: "memcached", Protocol: "udp", Port: 11211}}, cfg.Compute.Firewall.SensitivePorts)
+
+	// Ports in the file are validated
+	path = writeConfigFile(t, "ports.json", `[{"name": "memcached", "protocol": "udp", "port": 0}]`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	_, err = Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--project.filter=my-project", "--compute.firewall.sensitive-ports-file", path})
+	assert.NotNil(t, err)
+}
+
+func TestLoadErrors(t *testing.T) {
+
+	// Unknown keys in the config file are rejected
+	path := writeConfigFile(t, "nemesis.yaml", "projects:\n  filtr: my-project\n")
+	defer os.RemoveAll(filepath.Dir(path))
+	_, err := Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--config", path})
+	assert.NotNil(t, err)
+
+	// Malformed flag values are rejected while parsing
+	_, err = Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{"--project.filter=p", "--kms.key-rotation-time=never"})
+	assert.NotNil(t, err)
+
+	// The configuration is validated
+	_, err = Load(flag.NewFlagSet("nemesis", flag.ContinueOnError), []string{})
+	assert.NotNil(t, err)
+}
+
+func TestValidate(t *testing.T) {
+
+	cfg := Default()
+	cfg.Projects.Filter = "my-project"
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Projects.Scope = "projects/my-project"
+	cfg.IAM.APIKeyExpirationTime = 0
+	cfg.Reports.PubSub.Enable = true
+	cfg.Compute.Firewall.SensitivePorts = []SensitivePort{{Name: "redis", Protocol: "tcp", Port: 70000}}
+	cfg.Controls.Exclude = []string{"99.1"}
+	cfg.Profiles = []ProfileConfig{{Name: "production"}}
+	cfg.Threshold.Severity = "severe"
+	cfg.Reports.Stdout.Enable = true
+	cfg.Reports.Stdout.Format = "yaml"
+	cfg.Reports.CSV.Path = "-"
+	cfg.Reports.CSV.Columns = []string{"owner"}
+
+	err := cfg.Validate()
+	assert.NotNil(t, err)
+	for _, problem := range []string{"projects.scope", "iam.apiKeyExpirationTime", "reports.pubsub.project", "redis:tcp/70000", "99.1", "profiles", "threshold: severity", "reports.stdout.format", "reports.csv.path", "reports.csv.columns"} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestParseSensitivePorts(t *testing.T) {
+
+	// The default catalogue should round trip
+	ports, err := ParseSensitivePorts(formatSensitivePorts(defaultSensitivePorts))
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSensitivePorts, ports)
+
+	// Empty entries are ignored
+	ports, err = ParseSensitivePorts("redis:TCP/6379, ,")
+	assert.Nil(t, err)
+	assert.Equal(t, []SensitivePort{{Name: "redis", Protocol: "tcp", Port: 6379}}, ports)
+
+	// Malformed entries are reported
+	for _, invalid := range []string{"redis", "redis:6379", "redis:tcp/port", "redis:tcp/70000", ":tcp/22"} {
+		_, err = ParseSensitivePorts(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
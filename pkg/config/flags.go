@@ -0,0 +1,257 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// setting binds a configuration field to a command line flag and an environment variable
+type setting struct {
+	flag   string
+	env    string
+	usage  string
+	isBool bool
+	get    func(c *Config) string
+	set    func(c *Config, value string) error
+}
+
+// settings lists every configuration field that can be overridden by a flag or environment variable
+var settings = []setting{
+	// Debug
+	boolSetting("debug", "NEMESIS_DEBUG", "Enable verbose output for debugging", func(c *Config) *bool { return &c.Debug }),
+
+	// Projects
+	stringSetting("project.filter", "NEMESIS_PROJECT_FILTER", "The project filter to perform audits on. Required unless --project.scope is set", func(c *Config) *string { return &c.Projects.Filter }),
+	stringSetting("project.scope", "NEMESIS_PROJECT_SCOPE", "An organization or folder, e.g. organizations/123 or folders/456, to audit every active project beneath", func(c *Config) *string { return &c.Projects.Scope }),
+	listSetting("project.exclude", "NEMESIS_PROJECT_EXCLUDE", "A comma-separated list of folders (folders/456) and projects to exclude from the audit", func(c *Config) *[]string { return &c.Projects.Exclude }),
+
+	// Controls
+	listSetting("controls.include", "NEMESIS_CONTROLS_INCLUDE", "A comma-separated list of controls to evaluate, by CIS ID (4.6), CIS level (level1), scored, unscored or report type (compute_instance). All controls are evaluated by default", func(c *Config) *[]string { return &c.Controls.Include }),
+	listSetting("controls.exclude", "NEMESIS_CONTROLS_EXCLUDE", "A comma-separated list of controls to skip, using the same terms as --controls.include. Takes precedence over --controls.include", func(c *Config) *[]string { return &c.Controls.Exclude }),
+
+	// Waivers
+	stringSetting("waivers.file", "NEMESIS_WAIVERS_FILE", "A YAML or JSON file listing waivers, each matching a project, report type, resource and control, with a justification, owner and expiry date", func(c *Config) *string { return &c.Waivers.File }),
+
+	// Compute
+	intSetting("compute.instance.num-interfaces", "NEMESIS_COMPUTE_NUM_NICS", "The number of network interfaces (NIC) that an instance should have", func(c *Config) *int { return &c.Compute.Instance.NumInterfaces }),
+	boolSetting("compute.instance.allow-nat", "NEMESIS_COMPUTE_ALLOW_NAT", "Indicate whether instances should be allowed to have external (NAT) IP addresses", func(c *Config) *bool { return &c.Compute.Instance.AllowNat }),
+	boolSetting("compute.instance.allow-ip-forwarding", "NEMESIS_COMPUTE_ALLOW_IP_FORWARDING", "Indicate whether instances should be allowed to perform IP forwarding", func(c *Config) *bool { return &c.Compute.Instance.AllowIPForwarding }),
+	{
+		flag:  "compute.firewall.sensitive-ports",
+		env:   "NEMESIS_COMPUTE_SENSITIVE_PORTS",
+		usage: "A comma-separated list of services, formatted as name:protocol/port, that should not be reachable from the internet",
+		get:   func(c *Config) string { return formatSensitivePorts(c.Compute.Firewall.SensitivePorts) },
+		set: func(c *Config, value string) (err error) {
+			c.Compute.Firewall.SensitivePorts, err = ParseSensitivePorts(value)
+			return
+		},
+	},
+	stringSetting("compute.firewall.sensitive-ports-file", "NEMESIS_COMPUTE_SENSITIVE_PORTS_FILE", "A YAML or JSON file containing a list of services that should not be reachable from the internet. Takes precedence over --compute.firewall.sensitive-ports", func(c *Config) *string { return &c.Compute.Firewall.SensitivePortsFile }),
+
+	// Container
+	listSetting("container.oauth-scopes", "NEMESIS_CONTAINER_OAUTHSCOPES", "A comma-seperated list of OAuth scopes to allow for GKE clusters", func(c *Config) *[]string { return &c.Container.OAuthScopes }),
+
+	// IAM
+	listSetting("iam.user-domains", "NEMESIS_IAM_USERDOMAINS", "A comma-separated list of domains to allow users from", func(c *Config) *[]string { return &c.IAM.UserDomains }),
+	intSetting("iam.sa-key-expiration-time", "NEMESIS_IAM_SA_KEY_EXPIRATION_TIME", "The time in days to allow service account keys to live before being rotated", func(c *Config) *int { return &c.IAM.SAKeyExpirationTime }),
+	intSetting("iam.api-key-expiration-time", "NEMESIS_IAM_API_KEY_EXPIRATION_TIME", "The time in days to allow API keys to live before being rotated", func(c *Config) *int { return &c.IAM.APIKeyExpirationTime }),
+
+	// KMS
+	intSetting("kms.key-rotation-time", "NEMESIS_KMS_KEY_ROTATION_TIME", "The time in days to allow KMS crypto keys to go without being rotated", func(c *Config) *int { return &c.KMS.KeyRotationTime }),
+
+	// Organization policies
+	listSetting("orgpolicy.constraints", "NEMESIS_ORGPOLICY_CONSTRAINTS", "A comma-separated list of organization policy constraints that should be enforced on every project", func(c *Config) *[]string { return &c.OrgPolicy.Constraints }),
+
+	// Storage
+	intSetting("storage.object-acl-sample-size", "NEMESIS_STORAGE_OBJECT_ACL_SAMPLE_SIZE", "The number of objects per bucket to sample for public ACLs. Set to 0 to disable object sampling", func(c *Config) *int { return &c.Storage.ObjectACLSampleSize }),
+
+	// Metrics
+	boolSetting("metrics.enabled", "NEMESIS_METRICS_ENABLED", "Enable Prometheus metrics", func(c *Config) *bool { return &c.Metrics.Enabled }),
+	stringSetting("metrics.gateway", "NEMESIS_METRICS_GATEWAY", "Prometheus metrics Push Gateway", func(c *Config) *string { return &c.Metrics.Gateway }),
+
+	// Reports
+	boolSetting("reports.only-failures", "NEMESIS_ONLY_FAILURES", "Limit output of controls to only failed controls on stdout and Pub/Sub. Report files always include every control", func(c *Config) *bool { return &c.Reports.OnlyFailures }),
+	boolSetting("reports.stdout.enable", "NEMESIS_ENABLE_STDOUT", "Enable outputting report via stdout", func(c *Config) *bool { return &c.Reports.Stdout.Enable }),
+	stringSetting("reports.stdout.format", "NEMESIS_STDOUT_FORMAT", "The format of the stdout report: json, jsonl, table or markdown", func(c *Config) *string { return &c.Reports.Stdout.Format }),
+	boolSetting("reports.pubsub.enable", "NEMESIS_ENABLE_PUBSUB", "Enable outputting report via Google Pub/Sub", func(c *Config) *bool { return &c.Reports.PubSub.Enable }),
+	stringSetting("reports.pubsub.project", "NEMESIS_PUBSUB_PROJECT", "Indicate which GCP project to output Pub/Sub reports to", func(c *Config) *string { return &c.Reports.PubSub.Project }),
+	stringSetting("reports.pubsub.topic", "NEMESIS_PUBSUB_TOPIC", "Indicate which topic to output Pub/Sub reports to", func(c *Config) *string { return &c.Reports.PubSub.Topic }),
+	stringSetting("reports.sarif.path", "NEMESIS_SARIF_PATH", "Write a SARIF 2.1.0 log of failed controls to the given file path", func(c *Config) *string { return &c.Reports.Sarif.Path }),
+	stringSetting("reports.junit.path", "NEMESIS_JUNIT_PATH", "Write a JUnit XML report, with a test suite per report and a test case per control, to the given file path", func(c *Config) *string { return &c.Reports.JUnit.Path }),
+	stringSetting("reports.html.path", "NEMESIS_HTML_PATH", "Write a self-contained HTML compliance report to the given file path", func(c *Config) *string { return &c.Reports.HTML.Path }),
+	stringSetting("reports.csv.path", "NEMESIS_CSV_PATH", "Write a CSV file with one row per resource and control to the given file path, or to stdout when set to -", func(c *Config) *string { return &c.Reports.CSV.Path }),
+	listSetting("reports.csv.columns", "NEMESIS_CSV_COLUMNS", "A comma-separated list of columns to write to the CSV file", func(c *Config) *[]string { return &c.Reports.CSV.Columns }),
+
+	// Threshold
+	boolSetting("threshold.enable", "NEMESIS_THRESHOLD_ENABLE", "Exit with a non-zero status when more failed controls than the threshold tolerates are found", func(c *Config) *bool { return &c.Threshold.Enable }),
+	intSetting("threshold.level", "NEMESIS_THRESHOLD_LEVEL", "Only count failed CIS controls up to the given CIS level towards the threshold. Set to 0 to count every control", func(c *Config) *int { return &c.Threshold.Level }),
+	boolSetting("threshold.scored", "NEMESIS_THRESHOLD_SCORED", "Only count failed scored CIS controls towards the threshold", func(c *Config) *bool { return &c.Threshold.Scored }),
+	stringSetting("threshold.severity", "NEMESIS_THRESHOLD_SEVERITY", "Only count failed controls of at least the given severity (low, medium, high or critical) towards the threshold", func(c *Config) *string { return &c.Threshold.Severity }),
+	intSetting("threshold.max-failures", "NEMESIS_THRESHOLD_MAX_FAILURES", "The number of counted failed controls that are tolerated before exiting with a non-zero status", func(c *Config) *int { return &c.Threshold.MaxFailures }),
+}
+
+func stringSetting(name, env, usage string, field func(c *Config) *string) setting {
+	return setting{
+		flag:  name,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+func listSetting(name, env, usage string, field func(c *Config) *[]string) setting {
+	return setting{
+		flag:  name,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return strings.Join(*field(c), ",") },
+		set: func(c *Config, value string) error {
+			*field(c) = splitList(value)
+			return nil
+		},
+	}
+}
+
+func intSetting(name, env, usage string, field func(c *Config) *int) setting {
+	return setting{
+		flag:  name,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *Config, value string) error {
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("expected an integer: %v", err)
+			}
+			*field(c) = i
+			return nil
+		},
+	}
+}
+
+func boolSetting(name, env, usage string, field func(c *Config) *bool) setting {
+	return setting{
+		flag:   name,
+		env:    env,
+		usage:  usage,
+		isBool: true,
+		get:    func(c *Config) string { return strconv.FormatBool(*field(c)) },
+		set: func(c *Config, value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("expected a boolean: %v", err)
+			}
+			*field(c) = b
+			return nil
+		},
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	list := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// flagValue records the raw value of a flag, so flags can be applied after the config file and environment
+type flagValue struct {
+	s     setting
+	def   string
+	value string
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	if f.value != "" {
+		return f.value
+	}
+	return f.def
+}
+
+func (f *flagValue) Set(value string) error {
+	if err := f.s.set(Default(), value); err != nil {
+		return err
+	}
+	f.value = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.s.isBool
+}
+
+// Load registers the configuration flags on fs, parses args and returns the resulting configuration.
+// Settings are layered from lowest to highest precedence: defaults, the --config file, environment
+// variables and flags set on the command line. The configuration is validated before being returned.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+
+	defaults := Default()
+	configFile := fs.String("config", os.Getenv("NEMESIS_CONFIG"), "A YAML or JSON configuration file. Environment variables and flags take precedence over it")
+
+	values := make(map[string]*flagValue, len(settings))
+	for _, s := range settings {
+		v := &flagValue{s: s, def: s.get(defaults)}
+		values[s.flag] = v
+		fs.Var(v, s.flag, s.usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok && value != "" {
+			if err := s.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("Invalid value %q for %v: %v", value, s.env, err)
+			}
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if v, ok := values[f.Name]; ok && err == nil {
+			err = v.s.set(cfg, v.value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Compute.Firewall.SensitivePortsFile != "" {
+		if err := cfg.loadSensitivePortsFile(cfg.Compute.Firewall.SensitivePortsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Waivers.File != "" {
+		if err := cfg.loadWaiversFile(cfg.Waivers.File); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SensitivePort is a service that should not be reachable from the internet
+type SensitivePort struct {
+	Name     string `yaml:"name"`
+	Protocol string `yaml:"protocol"`
+	Port     int    `yaml:"port"`
+}
+
+// String returns the port formatted as name:protocol/port
+func (p SensitivePort) String() string {
+	return fmt.Sprintf("%v:%v/%v", p.Name, p.Protocol, p.Port)
+}
+
+// Validate returns an error if the sensitive port is incomplete or out of range
+func (p SensitivePort) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("Sensitive port %v must have a name", p)
+	}
+	if p.Protocol == "" {
+		return fmt.Errorf("Sensitive port %v must have a protocol", p)
+	}
+	if p.Port < 1 || p.Port > 65535 {
+		return fmt.Errorf("Sensitive port %v must be between 1 and 65535", p)
+	}
+	return nil
+}
+
+// ParseSensitivePorts parses a comma-separated list of ports formatted as name:protocol/port
+func ParseSensitivePorts(list string) ([]SensitivePort, error) {
+
+	ports := []SensitivePort{}
+
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		nameAndPort := strings.SplitN(entry, ":", 2)
+		if len(nameAndPort) != 2 {
+			return nil, fmt.Errorf("Invalid sensitive port '%v', expected name:protocol/port", entry)
+		}
+
+		protocolAndPort := strings.SplitN(nameAndPort[1], "/", 2)
+		if len(protocolAndPort) != 2 {
+			return nil, fmt.Errorf("Invalid sensitive port '%v', expected name:protocol/port", entry)
+		}
+
+		port, err := strconv.Atoi(protocolAndPort[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid port in sensitive port '%v': %v", entry, err)
+		}
+
+		p := SensitivePort{Name: nameAndPort[0], Protocol: strings.ToLower(protocolAndPort[0]), Port: port}
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		ports = append(ports, p)
+	}
+
+	return ports, nil
+}
+
+// formatSensitivePorts formats a list of ports as a comma-separated list of name:protocol/port
+func formatSensitivePorts(ports []SensitivePort) string {
+	entries := []string{}
+	for _, p := range ports {
+		entries = append(entries, p.String())
+	}
+	return strings.Join(entries, ",")
+}
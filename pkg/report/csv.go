@@ -0,0 +1,148 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CSVStdout is the CSV path that writes rows to stdout instead of a file
+	CSVStdout = "-"
+)
+
+var (
+	// DefaultCSVColumns are the columns written when no column set is configured
+	DefaultCSVColumns = []string{"type", "project", "resource", "control", "cisId", "level", "scored", "status", "error"}
+
+	// csvColumns renders every supported column from a report and one of its controls
+	csvColumns = map[string]func(r *Report, c *Control) string{
+		"type":         func(r *Report, c *Control) string { return r.Type },
+		"project":      func(r *Report, c *Control) string { return r.ProjectID },
+		"resource":     func(r *Report, c *Control) string { return r.Title },
+		"resourceName": func(r *Report, c *Control) string { return r.ResourceName },
+		"location":     func(r *Report, c *Control) string { return r.Location },
+		"control":      func(r *Report, c *Control) string { return c.Title },
+		"controlId":    func(r *Report, c *Control) string { return c.ID },
+		"severity":     func(r *Report, c *Control) string { return c.Severity },
+		"cisId": func(r *Report, c *Control) string {
+			if rec := c.Recommendation(); rec != nil {
+				return rec.CisID
+			}
+			return ""
+		},
+		"level": func(r *Report, c *Control) string {
+			if rec := c.Recommendation(); rec != nil {
+				return strconv.Itoa(rec.Level)
+			}
+			return ""
+		},
+		"scored": func(r *Report, c *Control) string {
+			if rec := c.Recommendation(); rec != nil {
+				return strconv.FormatBool(rec.Scored)
+			}
+			return ""
+		},
+		"status": func(r *Report, c *Control) string { return c.Status },
+		"error":  func(r *Report, c *Control) string { return c.Error },
+		"detail": func(r *Report, c *Control) string { return c.Detail },
+		"gcloud": func(r *Report, c *Control) string { return c.Gcloud },
+		"runId":  func(r *Report, c *Control) string { return r.RunID },
+		"timestamp": func(r *Report, c *Control) string {
+			if r.Timestamp.IsZero() {
+				return ""
+			}
+			return r.Timestamp.UTC().Format(time.RFC3339)
+		},
+	}
+)
+
+// ValidateCSVColumns checks that every column of a column set is supported
+func ValidateCSVColumns(columns []string) error {
+	unknown := []string{}
+	for _, name := range columns {
+		if _, ok := csvColumns[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	supported := make([]string, 0, len(csvColumns))
+	for name := range csvColumns {
+		supported = append(supported, name)
+	}
+	sort.Strings(supported)
+	return fmt.Errorf("Unknown CSV columns %v, supported columns are %v", strings.Join(unknown, ", "), strings.Join(supported, ", "))
+}
+
+// CSVReporter is a reporter that flattens audit reports into CSV, with one row per resource and control
+type CSVReporter struct {
+	path    string
+	columns []string
+	w       io.Writer
+}
+
+// NewCSVReporter returns a new CSVReporter writing the given columns to a file path, or to stdout when the path is "-".
+// The default columns are written when no columns are given
+func NewCSVReporter(path string, columns []string) *CSVReporter {
+	r := new(CSVReporter)
+	r.path = path
+	r.columns = columns
+	if len(r.columns) == 0 {
+		r.columns = DefaultCSVColumns
+	}
+	if path == CSVStdout {
+		r.w = os.Stdout
+	}
+	return r
+}
+
+// Publish writes a header row and a row for every control of every report
+func (r *CSVReporter) Publish(reports []Report) error {
+	if err := ValidateCSVColumns(r.columns); err != nil {
+		return err
+	}
+
+	if r.w != nil {
+		return r.write(r.w, reports)
+	}
+
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if err := r.write(f, reports); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (r *CSVReporter) write(w io.Writer, reports []Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.columns); err != nil {
+		return err
+	}
+
+	row := make([]string, len(r.columns))
+	for i := range reports {
+		for j := range reports[i].Controls {
+			for k, name := range r.columns {
+				row[k] = csvColumns[name](&reports[i], &reports[i].Controls[j])
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVReporter(t *testing.T) {
+
+	var out bytes.Buffer
+	r := NewCSVReporter(CSVStdout, nil)
+	r.w = &out
+	assert.Nil(t, r.Publish(testStdOutReports()))
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	assert.Nil(t, err)
+
+	// A header row, then one row per resource and control
+	assert.Len(t, rows, 6)
+	assert.Equal(t, DefaultCSVColumns, rows[0])
+	assert.Equal(t, []string{
+		"storage_bucket",
+		"my-project",
+		"Project my-project Storage Bucket my-bucket",
+		"CIS 5.1 - Ensure that Cloud Storage bucket is not anonymously or publicly accessible (Scored)",
+		"5.1",
+		"1",
+		"true",
+		"failed",
+		"Bucket my-bucket allows allUsers | allAuthenticatedUsers",
+	}, rows[1])
+}
+
+func TestCSVReporterColumns(t *testing.T) {
+
+	report := NewReport("compute_instance", "Project my-project Compute Instance my-instance")
+	report.ProjectID = "my-project"
+	report.AddControls(NewControl("hasNatIP=false", "Compute Instance should not have a NAT ip configured"))
+
+	path := filepath.Join(os.TempDir(), "nemesis-test.csv")
+	defer os.Remove(path)
+	assert.Nil(t, NewCSVReporter(path, []string{"project", "controlId", "severity", "cisId", "status"}).Publish([]Report{report}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "project,controlId,severity,cisId,status\nmy-project,compute-external-ip,medium,,failed\n", string(b))
+
+	// Unknown columns are rejected
+	assert.NotNil(t, ValidateCSVColumns([]string{"project", "owner"}))
+	assert.NotNil(t, NewCSVReporter(path, []string{"owner"}).Publish([]Report{report}))
+}
@@ -0,0 +1,291 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"sort"
+
+	"github.com/UnityTech/nemesis/pkg/catalogue"
+	"github.com/UnityTech/nemesis/pkg/cis"
+)
+
+var (
+	htmlTemplate = template.Must(template.New("report").Parse(htmlReportTemplate))
+)
+
+// HTMLReporter is a reporter that writes audit reports to a self-contained HTML file, with an executive summary, drill-down
+// tables per control and the raw data of each report. All styles are embedded, so the file can be opened offline
+type HTMLReporter struct {
+	path string
+}
+
+// NewHTMLReporter returns a new HTMLReporter writing to the given file path
+func NewHTMLReporter(path string) *HTMLReporter {
+	r := new(HTMLReporter)
+	r.path = path
+	return r
+}
+
+// Publish renders the reports to the HTML file
+func (r *HTMLReporter) Publish(reports []Report) error {
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, newHTMLSummary(reports)); err != nil {
+		return fmt.Errorf("Failed to render HTML report: %v", err)
+	}
+	return ioutil.WriteFile(r.path, b.Bytes(), 0644)
+}
+
+// htmlRate counts the control statuses of a group of controls. Waived controls count as passing
+type htmlRate struct {
+	Name          string
+	Passed        int
+	Failed        int
+	Waived        int
+	NotApplicable int
+	Skipped       int
+}
+
+func (r *htmlRate) add(status string) {
+	switch status {
+	case Passed:
+		r.Passed++
+	case Failed:
+		r.Failed++
+	case Waived:
+		r.Waived++
+	case NotApplicable:
+		r.NotApplicable++
+	case Skipped:
+		r.Skipped++
+	}
+}
+
+// Percent returns the percentage of evaluated controls that passed, or -1 when no control was evaluated
+func (r *htmlRate) Percent() int {
+	total := r.Passed + r.Waived + r.Failed
+	if total == 0 {
+		return -1
+	}
+	return (r.Passed + r.Waived) * 100 / total
+}
+
+// Rate returns the pass rate formatted for display
+func (r *htmlRate) Rate() string {
+	if r.Percent() < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d%%", r.Percent())
+}
+
+// htmlFailure is a resource that failed a control
+type htmlFailure struct {
+	Project  string
+	Resource string
+	Error    string
+	Gcloud   string
+}
+
+// htmlControl is the drill-down of a control across every resource it was evaluated on
+type htmlControl struct {
+	htmlRate
+	ID          string
+	Severity    string
+	Remediation string
+	References  []string
+	Failures    []htmlFailure
+}
+
+// htmlReport is a report with its raw data
+type htmlReport struct {
+	Title   string
+	Type    string
+	Project string
+	Status  string
+	Data    string
+}
+
+// htmlSummary is the data rendered by the HTML template
+type htmlSummary struct {
+	RunID     string
+	Timestamp string
+	Version   string
+	Overall   htmlRate
+	Sections  []*htmlRate
+	Projects  []*htmlRate
+	Controls  []*htmlControl
+	Reports   []htmlReport
+}
+
+// newHTMLSummary computes the overall score, pass rates per CIS section and project, and the drill-down of every control
+func newHTMLSummary(reports []Report) *htmlSummary {
+	s := &htmlSummary{Overall: htmlRate{Name: "Overall"}}
+
+	sections := map[string]*htmlRate{}
+	for _, name := range cis.Sections {
+		sections[name] = &htmlRate{Name: name}
+		s.Sections = append(s.Sections, sections[name])
+	}
+	projects := map[string]*htmlRate{}
+	controls := map[string]*htmlControl{}
+
+	for i := range reports {
+		r := &reports[i]
+		if s.RunID == "" && r.RunID != "" {
+			s.RunID = r.RunID
+			s.Timestamp = r.Timestamp.UTC().Format("2006-01-02 15:04:05 MST")
+			s.Version = r.Version
+		}
+
+		project := r.ProjectID
+		if project == "" {
+			project = "(no project)"
+		}
+		if _, ok := projects[project]; !ok {
+			projects[project] = &htmlRate{Name: project}
+			s.Projects = append(s.Projects, projects[project])
+		}
+
+		resource := r.Resource
+		if resource == "" {
+			resource = r.Title
+		}
+
+		for j := range r.Controls {
+			c := &r.Controls[j]
+			s.Overall.add(c.Status)
+			projects[project].add(c.Status)
+			if rec := c.Recommendation(); rec != nil {
+				if section, ok := sections[rec.Section()]; ok {
+					section.add(c.Status)
+				}
+			}
+
+			hc, ok := controls[c.Title]
+			if !ok {
+				hc = &htmlControl{
+					htmlRate:    htmlRate{Name: c.Title},
+					ID:          c.ID,
+					Severity:    c.Severity,
+					Remediation: c.Remediation,
+					References:  c.References,
+				}
+				controls[c.Title] = hc
+				s.Controls = append(s.Controls, hc)
+			}
+			hc.add(c.Status)
+			if c.Status == Failed {
+				msg := c.Error
+				if msg == "" {
+					msg = c.Desc
+				}
+				hc.Failures = append(hc.Failures, htmlFailure{Project: project, Resource: resource, Error: msg, Gcloud: c.Gcloud})
+			}
+		}
+
+		s.Reports = append(s.Reports, htmlReport{
+			Title:   r.Title,
+			Type:    r.Type,
+			Project: project,
+			Status:  r.Status(),
+			Data:    indentJSON(r.Data),
+		})
+	}
+
+	sort.SliceStable(s.Projects, func(i, j int) bool { return s.Projects[i].Name < s.Projects[j].Name })
+
+	// Controls with the most failures come first, then the most severe
+	sort.SliceStable(s.Controls, func(i, j int) bool {
+		a, b := s.Controls[i], s.Controls[j]
+		if a.Failed != b.Failed {
+			return a.Failed > b.Failed
+		}
+		if catalogue.Rank(a.Severity) != catalogue.Rank(b.Severity) {
+			return catalogue.Rank(a.Severity) > catalogue.Rank(b.Severity)
+		}
+		return a.Name < b.Name
+	})
+	return s
+}
+
+// indentJSON pretty prints raw JSON data, returning it unchanged if it is not valid JSON
+func indentJSON(data json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return b.String()
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>nemesis compliance report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1, h2 { border-bottom: 1px solid #e1e4e8; padding-bottom: .3em; }
+table { border-collapse: collapse; margin: 1em 0; width: 100%; }
+th, td { border: 1px solid #e1e4e8; padding: .4em .6em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.number { text-align: right; }
+.score { font-size: 3em; font-weight: bold; }
+.bar { background: #f0f0f0; width: 12em; height: 1em; }
+.bar div { background: #2ea44f; height: 1em; }
+.failed { color: #cb2431; font-weight: bold; }
+.passed { color: #22863a; }
+.severity-critical, .severity-high { color: #cb2431; }
+.severity-medium { color: #b08800; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+code { font-size: .9em; }
+summary { cursor: pointer; }
+</style>
+</head>
+<body>
+<h1>nemesis compliance report</h1>
+{{if .RunID}}<p>Scan {{.RunID}} at {{.Timestamp}}, nemesis {{.Version}}</p>{{end}}
+
+<h2>Executive summary</h2>
+<p class="score">{{.Overall.Rate}}</p>
+<p>{{.Overall.Passed}} passed, <span class="failed">{{.Overall.Failed}} failed</span>, {{.Overall.Waived}} waived, {{.Overall.Skipped}} skipped and {{.Overall.NotApplicable}} not applicable controls across {{len .Reports}} resources.</p>
+
+<h3>Pass rate per CIS section</h3>
+<table>
+<tr><th>Section</th><th>Pass rate</th><th></th><th>Passed</th><th>Failed</th><th>Waived</th></tr>
+{{range .Sections}}<tr><td>{{.Name}}</td><td class="number">{{.Rate}}</td><td><div class="bar">{{if ge .Percent 0}}<div style="width: {{.Percent}}%"></div>{{end}}</div></td><td class="number">{{.Passed}}</td><td class="number">{{.Failed}}</td><td class="number">{{.Waived}}</td></tr>
+{{end}}</table>
+
+<h3>Pass rate per project</h3>
+<table>
+<tr><th>Project</th><th>Pass rate</th><th></th><th>Passed</th><th>Failed</th><th>Waived</th></tr>
+{{range .Projects}}<tr><td>{{.Name}}</td><td class="number">{{.Rate}}</td><td><div class="bar">{{if ge .Percent 0}}<div style="width: {{.Percent}}%"></div>{{end}}</div></td><td class="number">{{.Passed}}</td><td class="number">{{.Failed}}</td><td class="number">{{.Waived}}</td></tr>
+{{end}}</table>
+
+<h2>Controls</h2>
+<table>
+<tr><th>Control</th><th>Severity</th><th>Pass rate</th><th>Passed</th><th>Failed</th><th>Waived</th></tr>
+{{range .Controls}}<tr><td>{{.Name}}{{if .ID}}<br><code>{{.ID}}</code>{{end}}</td><td class="severity-{{.Severity}}">{{.Severity}}</td><td class="number">{{.Rate}}</td><td class="number">{{.Passed}}</td><td class="number">{{.Failed}}</td><td class="number">{{.Waived}}</td></tr>
+{{if .Failures}}<tr><td colspan="6">
+<details>
+<summary>Failing resources ({{len .Failures}})</summary>
+{{if .Remediation}}<p><strong>Remediation:</strong> {{.Remediation}}</p>{{end}}
+{{if .References}}<p>{{range .References}}<a href="{{.}}">{{.}}</a><br>{{end}}</p>{{end}}
+<table>
+<tr><th>Project</th><th>Resource</th><th>Error</th><th>Remediation command</th></tr>
+{{range .Failures}}<tr><td>{{.Project}}</td><td>{{.Resource}}</td><td>{{.Error}}</td><td>{{if .Gcloud}}<code>{{.Gcloud}}</code>{{end}}</td></tr>
+{{end}}</table>
+</details>
+</td></tr>{{end}}
+{{end}}</table>
+
+<h2>Resources</h2>
+{{range .Reports}}<details>
+<summary><span class="{{.Status}}">{{.Status}}</span> {{.Title}} ({{.Type}})</summary>
+<pre>{{.Data}}</pre>
+</details>
+{{end}}
+</body>
+</html>
+`
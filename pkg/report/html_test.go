@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLSummary(t *testing.T) {
+
+	reports := testStdOutReports()
+	reports[0].Data = json.RawMessage(`{"name":"my-bucket"}`)
+	s := newHTMLSummary(reports)
+
+	// 3 of 5 controls passed
+	assert.Equal(t, 60, s.Overall.Percent())
+	assert.Equal(t, "60%", s.Overall.Rate())
+
+	// Pass rates per CIS section, in the order of the benchmark
+	assert.Len(t, s.Sections, 7)
+	assert.Equal(t, "Virtual Machines", s.Sections[3].Name)
+	assert.Equal(t, "0%", s.Sections[3].Rate())
+	assert.Equal(t, "Storage", s.Sections[4].Name)
+	assert.Equal(t, "75%", s.Sections[4].Rate())
+	assert.Equal(t, "n/a", s.Sections[0].Rate())
+
+	// Pass rates per project, sorted by project
+	assert.Equal(t, "another-project", s.Projects[0].Name)
+	assert.Equal(t, "my-project", s.Projects[1].Name)
+	assert.Equal(t, "75%", s.Projects[1].Rate())
+
+	// Controls with failures come first, with the failing resources
+	assert.Equal(t, "cis-5.1", s.Controls[0].ID)
+	assert.Equal(t, []htmlFailure{{
+		Project:  "my-project",
+		Resource: "my-bucket",
+		Error:    "Bucket my-bucket allows allUsers | allAuthenticatedUsers",
+		Gcloud:   "gsutil iam ch -d allUsers -d allAuthenticatedUsers gs://my-bucket",
+	}}, s.Controls[0].Failures)
+	assert.Equal(t, "{\n  \"name\": \"my-bucket\"\n}", s.Reports[0].Data)
+}
+
+func TestHTMLReporter(t *testing.T) {
+
+	reports := testStdOutReports()
+	reports[0].Data = json.RawMessage(`{"name":"<script>alert(1)</script>"}`)
+
+	path := filepath.Join(os.TempDir(), "nemesis-test.html")
+	defer os.Remove(path)
+	assert.Nil(t, NewHTMLReporter(path).Publish(reports))
+
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	html := string(b)
+	assert.Contains(t, html, "<h2>Executive summary</h2>")
+	assert.Contains(t, html, "<details>")
+
+	// Resource data is escaped, and no assets are fetched
+	assert.NotContains(t, html, "<script>")
+	assert.NotContains(t, html, "<link")
+	assert.NotContains(t, html, "src=")
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+}
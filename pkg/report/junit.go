@@ -0,0 +1,168 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// JUnitReporter is a reporter that writes audit reports to a JUnit XML file, so CI pipelines can show them as test results.
+// Each report is a test suite and each control a test case
+type JUnitReporter struct {
+	path string
+}
+
+// NewJUnitReporter returns a new JUnitReporter writing to the given file path
+func NewJUnitReporter(path string) *JUnitReporter {
+	r := new(JUnitReporter)
+	r.path = path
+	return r
+}
+
+// Publish writes the reports to the JUnit XML file
+func (r *JUnitReporter) Publish(reports []Report) error {
+	b, err := xml.MarshalIndent(newJUnitTestSuites(reports), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to render JUnit report: %v", err)
+	}
+	return ioutil.WriteFile(r.path, append([]byte(xml.Header), b...), 0644)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	ID         int             `xml:"id,attr"`
+	Package    string          `xml:"package,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// junitClassName returns the class name of a report's test cases, which CI test UIs group test cases by
+func junitClassName(r *Report) string {
+	parts := []string{}
+	for _, p := range []string{r.ProjectID, r.Type, r.Resource} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// newJUnitTestCase returns the test case of a control. Failed controls carry their error, while controls that were
+// skipped, waived or not applicable are skipped test cases
+func newJUnitTestCase(r *Report, c *Control) junitTestCase {
+	tc := junitTestCase{
+		Name:      c.Title,
+		ClassName: junitClassName(r),
+		Time:      "0",
+	}
+
+	switch c.Status {
+	case Failed:
+		message := c.Error
+		if message == "" {
+			message = c.Desc
+		}
+		lines := []string{c.Desc}
+		for _, line := range []string{c.Detail, c.Remediation, c.Gcloud} {
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+		tc.Failure = &junitFailure{Message: message, Type: c.Severity, Text: strings.Join(lines, "\n")}
+	case Skipped, Waived:
+		tc.Skipped = &junitSkipped{Message: c.Detail}
+	case NotApplicable:
+		tc.Skipped = &junitSkipped{Message: c.Error}
+	}
+	return tc
+}
+
+func newJUnitTestSuites(reports []Report) junitTestSuites {
+	suites := junitTestSuites{
+		Name:   "nemesis",
+		Suites: make([]junitTestSuite, 0, len(reports)),
+	}
+
+	for i := range reports {
+		r := &reports[i]
+		suite := junitTestSuite{
+			Name:      r.Title,
+			ID:        i,
+			Package:   r.Type,
+			Time:      "0",
+			TestCases: make([]junitTestCase, 0, len(r.Controls)),
+		}
+		if !r.Timestamp.IsZero() {
+			suite.Timestamp = r.Timestamp.UTC().Format("2006-01-02T15:04:05")
+		}
+		for _, p := range []junitProperty{
+			{"projectId", r.ProjectID},
+			{"resource", r.Resource},
+			{"resourceName", r.ResourceName},
+			{"location", r.Location},
+			{"runId", r.RunID},
+			{"version", r.Version},
+		} {
+			if p.Value != "" {
+				suite.Properties = append(suite.Properties, p)
+			}
+		}
+
+		for j := range r.Controls {
+			tc := newJUnitTestCase(r, &r.Controls[j])
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+			if tc.Skipped != nil {
+				suite.Skipped++
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suite.Tests = len(suite.TestCases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnitReporter(t *testing.T) {
+
+	r := NewReport("storage_bucket", "Project my-project Storage Bucket my-bucket")
+	r.ProjectID = "my-project"
+	r.Resource = "my-bucket"
+	r.RunID = "2c5ea4c0-4067-4b1b-8d2b-8b6d1c0a3e7f"
+	public := NewCISControl("5.1", "Bucket should not be public")
+	public.Error = "Bucket my-bucket allows allUsers"
+	logging := NewCISControl("5.3", "Bucket should have access logging enabled")
+	logging.Passed()
+	policyOnly := NewControl("bucketPolicyOnly", "Bucket should have Bucket Policy Only enabled")
+	policyOnly.NotApplicable("Bucket is managed elsewhere")
+	r.AddControls(public, logging, policyOnly)
+
+	path := filepath.Join(os.TempDir(), "nemesis-test-junit.xml")
+	defer os.Remove(path)
+	assert.Nil(t, NewJUnitReporter(path).Publish([]Report{r}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(b), xml.Header))
+
+	var suites junitTestSuites
+	assert.Nil(t, xml.Unmarshal(b, &suites))
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+
+	// Each report is a test suite
+	suite := suites.Suites[0]
+	assert.Equal(t, "Project my-project Storage Bucket my-bucket", suite.Name)
+	assert.Equal(t, "storage_bucket", suite.Package)
+	assert.Contains(t, suite.Properties, junitProperty{"runId", "2c5ea4c0-4067-4b1b-8d2b-8b6d1c0a3e7f"})
+
+	// Each control is a test case, and failed controls carry their error
+	assert.Len(t, suite.TestCases, 3)
+	failed := suite.TestCases[0]
+	assert.Equal(t, "my-project.storage_bucket.my-bucket", failed.ClassName)
+	assert.Equal(t, "Bucket my-bucket allows allUsers", failed.Failure.Message)
+	assert.Equal(t, "critical", failed.Failure.Type)
+	assert.Contains(t, failed.Failure.Text, "gsutil iam ch -d allUsers -d allAuthenticatedUsers gs://my-bucket")
+	assert.Nil(t, suite.TestCases[1].Failure)
+	assert.Nil(t, suite.TestCases[1].Skipped)
+	assert.Equal(t, "Bucket is managed elsewhere", suite.TestCases[2].Skipped.Message)
+}
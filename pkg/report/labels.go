@@ -0,0 +1,38 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ExemptLabel is the resource label listing the CIS controls that do not apply to a resource.
+	// Label values cannot contain dots, so CIS IDs may be written with dashes (4-6) and are separated by underscores (4-6_5-1)
+	ExemptLabel = "nemesis-exempt"
+)
+
+// parseExemptions returns the CIS IDs listed in the value of an exempt label
+func parseExemptions(value string) map[string]bool {
+	exemptions := map[string]bool{}
+	for _, id := range strings.FieldsFunc(value, func(r rune) bool { return r == '_' || r == ',' }) {
+		exemptions[strings.Replace(id, "-", ".", -1)] = true
+	}
+	return exemptions
+}
+
+// isExempt returns whether the labels of the report's resource exempt it from a control
+func (r *Report) isExempt(c Control) bool {
+	value, ok := r.Labels[ExemptLabel]
+	rec := c.Recommendation()
+	if !ok || rec == nil {
+		return false
+	}
+	return parseExemptions(value)[rec.CisID]
+}
+
+// Exempt marks the control as not evaluated because the resource is exempt from it by a label
+func (c *Control) Exempt(label string, value string) {
+	c.Status = Skipped
+	c.Error = ""
+	c.Detail = fmt.Sprintf("Exempted by resource label %v=%v", label, value)
+}
@@ -0,0 +1,26 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddControlsExemptsLabelledResources(t *testing.T) {
+
+	r := NewReport("compute_instance", "Project my-project Compute Instance nat-gateway")
+	r.Labels[ExemptLabel] = "4-5_4-6"
+	r.AddControls(
+		NewCISControl("4.4", "Serial ports should be disabled"),
+		NewCISControl("4.5", "IP forwarding should be disabled"),
+		NewCISControl("4.6", "Disks should be encrypted with CSEK"),
+	)
+
+	assert.Equal(t, Failed, r.Controls[0].Status)
+	assert.Equal(t, Skipped, r.Controls[1].Status)
+	assert.Equal(t, "Exempted by resource label nemesis-exempt=4-5_4-6", r.Controls[1].Detail)
+	assert.Equal(t, Skipped, r.Controls[2].Status)
+
+	assert.Equal(t, map[string]bool{"4.6": true}, parseExemptions("4.6"))
+	assert.Equal(t, map[string]bool{"4.6": true, "5.1": true}, parseExemptions("4-6,5-1"))
+}
@@ -51,7 +51,8 @@ func (r *PubSubReporter) Publish(reports []Report) error {
 		}
 
 		result := topic.Publish(ctx, &pubsub.Message{
-			Data: data,
+			Data:       data,
+			Attributes: messageAttributes(&reports[i]),
 		})
 
 		wg.Add(1)
@@ -74,3 +75,24 @@ func (r *PubSubReporter) Publish(reports []Report) error {
 
 	return nil
 }
+
+// messageAttributes returns the attributes of a report's message, so subscribers can filter and deduplicate findings
+// without decoding the report
+func messageAttributes(r *Report) map[string]string {
+	attrs := map[string]string{
+		"type":   r.Type,
+		"status": r.Status(),
+	}
+	for k, v := range map[string]string{
+		"projectId":    r.ProjectID,
+		"resourceName": r.ResourceName,
+		"resource":     r.Resource,
+		"runId":        r.RunID,
+		"version":      r.Version,
+	} {
+		if v != "" {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
@@ -1 +1,26 @@
 package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageAttributes(t *testing.T) {
+
+	r := NewReport("storage_bucket", "Project my-project Storage Bucket my-bucket")
+	r.ProjectID = "my-project"
+	r.Resource = "my-bucket"
+	r.ResourceName = "//storage.googleapis.com/projects/_/buckets/my-bucket"
+	r.RunID = "2c5ea4c0-4067-4b1b-8d2b-8b6d1c0a3e7f"
+	r.AddControls(NewCISControl("5.1", "Bucket should not be public"))
+
+	assert.Equal(t, map[string]string{
+		"type":         "storage_bucket",
+		"status":       Failed,
+		"projectId":    "my-project",
+		"resource":     "my-bucket",
+		"resourceName": "//storage.googleapis.com/projects/_/buckets/my-bucket",
+		"runId":        "2c5ea4c0-4067-4b1b-8d2b-8b6d1c0a3e7f",
+	}, messageAttributes(&r))
+}
@@ -3,7 +3,10 @@ package report
 
 import (
 	"encoding/json"
+	"fmt"
+	"time"
 
+	"github.com/UnityTech/nemesis/pkg/catalogue"
 	"github.com/UnityTech/nemesis/pkg/cis"
 	"github.com/golang/glog"
 )
@@ -14,19 +17,39 @@ const (
 
 	// Passed indicates that a resource met the expected spec
 	Passed = "passed"
+
+	// NotApplicable indicates that the control does not apply to the resource
+	NotApplicable = "not_applicable"
+
+	// Skipped indicates that the control was deliberately left out of the audit by the control selection
+	Skipped = "skipped"
+
+	// Waived indicates that the control failed, but the risk was accepted by a waiver that has not expired
+	Waived = "waived"
 )
 
-// Control is a measurable unit of an audit
+// Control is a measurable unit of an audit. Its catalogue entry describes the severity of a failure and how to remediate it
 type Control struct {
-	Title  string `json:"title"`
-	Desc   string `json:"desc"`
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	catalogue.Entry
+
+	Title  string  `json:"title"`
+	Desc   string  `json:"desc"`
+	Status string  `json:"status"`
+	Error  string  `json:"error,omitempty"`
+	Detail string  `json:"detail,omitempty"`
+	Waiver *Waiver `json:"waiver,omitempty"`
+
+	recommendation *cis.Recommendation
 }
 
 // NewControl returns a new Control with the given title
 func NewControl(title string, desc string) Control {
+	entry, ok := catalogue.Lookup(title)
+	if !ok {
+		glog.Fatalf("Couldn't find catalogue entry for control '%v'", title)
+	}
 	return Control{
+		Entry:  entry,
 		Title:  title,
 		Desc:   desc,
 		Status: Failed,
@@ -39,24 +62,74 @@ func NewCISControl(recommendationID string, desc string) Control {
 	if !ok {
 		glog.Fatalf("Couldn't find CIS recommendation with ID '%v'", recommendationID)
 	}
+	entry, ok := catalogue.Lookup(recommendationID)
+	if !ok {
+		glog.Fatalf("Couldn't find catalogue entry for CIS recommendation '%v'", recommendationID)
+	}
 	return Control{
-		Title:  rec.Format(),
-		Desc:   desc,
-		Status: Failed,
+		Entry:          entry,
+		Title:          rec.Format(),
+		Desc:           desc,
+		Status:         Failed,
+		recommendation: &rec,
 	}
 }
 
+// Recommendation returns the CIS recommendation of the control, or nil if it is not a CIS control
+func (c *Control) Recommendation() *cis.Recommendation {
+	return c.recommendation
+}
+
 // Passed changes the status of the control from `false` to `true`.
 func (c *Control) Passed() {
 	c.Status = Passed
 }
 
+// PassedBy changes the status of the control to passed, recording what guarantees compliance.
+// Any error found while evaluating the resource itself is kept in the detail
+func (c *Control) PassedBy(reason string) {
+	if c.Error != "" {
+		reason = fmt.Sprintf("%v (%v)", reason, c.Error)
+	}
+	c.Status = Passed
+	c.Error = ""
+	c.Detail = reason
+}
+
+// Skip marks the control as deliberately left out of the audit, discarding its result
+func (c *Control) Skip() {
+	c.Status = Skipped
+	c.Error = ""
+	c.Detail = "Not selected by the control selection"
+}
+
+// NotApplicable marks the control as not relevant to the resource, along with the reason why
+func (c *Control) NotApplicable(reason string) {
+	c.Status = NotApplicable
+	c.Error = reason
+}
+
 // Report is a top-level structure for capturing information generated from an audit on a resource
 type Report struct {
-	Type     string          `json:"type"`
-	Title    string          `json:"title"`
-	Controls []Control       `json:"controls"`
-	Data     json.RawMessage `json:"data"`
+	Type           string            `json:"type"`
+	Title          string            `json:"title"`
+	OrganizationID string            `json:"organizationId,omitempty"`
+	FolderPath     string            `json:"folderPath,omitempty"`
+	ProjectID      string            `json:"projectId,omitempty"`
+	ProjectNumber  string            `json:"projectNumber,omitempty"`
+	Resource       string            `json:"resource,omitempty"`
+	ResourceName   string            `json:"resourceName,omitempty"`
+	Location       string            `json:"location,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Profile        string            `json:"profile,omitempty"`
+	RunID          string            `json:"runId,omitempty"`
+	Timestamp      time.Time         `json:"timestamp"`
+	Version        string            `json:"version,omitempty"`
+	Controls       []Control         `json:"controls"`
+	Data           json.RawMessage   `json:"data"`
+
+	selector *Selector
+	waivers  []Waiver
 }
 
 // NewReport returns a new top-level report with a given title
@@ -65,10 +138,11 @@ func NewReport(typ string, title string) Report {
 		Type:     typ,
 		Title:    title,
 		Controls: []Control{},
+		Labels:   map[string]string{},
 	}
 }
 
-// Status returns whether a report passed all the controls it was assigned.
+// Status returns whether a report passed all the controls it was assigned. Waived controls count as passing
 func (r *Report) Status() string {
 	for _, c := range r.Controls {
 		if c.Status == Failed {
@@ -78,12 +152,64 @@ func (r *Report) Status() string {
 	return Passed
 }
 
-// AddControls appends controls to the report. If we only report failures, then controls that pass are not included in the report
+// SelectControls sets the selector deciding which controls added to the report are evaluated
+func (r *Report) SelectControls(s *Selector) {
+	r.selector = s
+}
+
+// WaiveControls sets the waivers that are matched against failed controls added to the report
+func (r *Report) WaiveControls(waivers []Waiver) {
+	r.waivers = waivers
+}
+
+// matchWaiver returns the waiver covering a control, preferring waivers that have not expired
+func (r *Report) matchWaiver(c Control, now time.Time) *Waiver {
+	var expired *Waiver
+	for i := range r.waivers {
+		w := &r.waivers[i]
+		if !w.Matches(r, c) {
+			continue
+		}
+		if !w.IsExpired(now) {
+			return w
+		}
+		if expired == nil {
+			expired = w
+		}
+	}
+	return expired
+}
+
+// AddControls appends controls to the report. Controls that are not selected, or that the resource is exempt from
+// by its labels, are kept with a skipped status. Failed controls covered by a waiver are marked as waived
 func (r *Report) AddControls(controls ...Control) {
+	now := time.Now()
 	for _, c := range controls {
-		if c.Status == Passed && *flagReportOnlyFailures {
-			continue
+		c.Gcloud = c.Command(r.ProjectID, r.Resource)
+		if !r.selector.Selects(r.Type, c) {
+			c.Skip()
+		} else if r.isExempt(c) {
+			c.Exempt(ExemptLabel, r.Labels[ExemptLabel])
+		}
+		if w := r.matchWaiver(c, now); w != nil {
+			c.Waive(w, now)
 		}
 		r.Controls = append(r.Controls, c)
 	}
 }
+
+// OnlyFailures returns a copy of the reports where controls that did not fail are left out
+func OnlyFailures(reports []Report) []Report {
+	filtered := make([]Report, 0, len(reports))
+	for _, r := range reports {
+		controls := []Control{}
+		for _, c := range r.Controls {
+			if c.Status == Failed {
+				controls = append(controls, c)
+			}
+		}
+		r.Controls = controls
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
@@ -1 +1,41 @@
 package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlyFailures(t *testing.T) {
+
+	passed := NewControl("numNetworkInterfaces=1", "A passing control")
+	passed.Passed()
+	failed := NewControl("internetExposure", "A failing control")
+
+	r := NewReport("test", "Test Report")
+	r.AddControls(passed, failed)
+
+	filtered := OnlyFailures([]Report{r})
+	assert.Equal(t, []Control{failed}, filtered[0].Controls)
+
+	// The original reports are left untouched
+	assert.Len(t, r.Controls, 2)
+}
+
+func TestControlCatalogue(t *testing.T) {
+
+	c := NewCISControl("5.1", "Bucket my-bucket should not be anonymously or publicly accessible")
+	assert.Equal(t, "cis-5.1", c.ID)
+	assert.Equal(t, "critical", c.Severity)
+	assert.NotEmpty(t, c.Remediation)
+
+	custom := NewControl("hasNatIP=false", "Compute Instance should not have a NAT ip configured")
+	assert.Equal(t, "compute-external-ip", custom.ID)
+
+	// The gcloud command is rendered for the resource of the report
+	r := NewReport("storage_bucket", "Project my-project Storage Bucket my-bucket")
+	r.ProjectID = "my-project"
+	r.Resource = "my-bucket"
+	r.AddControls(c)
+	assert.Equal(t, "gsutil iam ch -d allUsers -d allAuthenticatedUsers gs://my-bucket", r.Controls[0].Gcloud)
+}
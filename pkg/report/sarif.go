@@ -0,0 +1,318 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/UnityTech/nemesis/pkg/catalogue"
+	"github.com/UnityTech/nemesis/pkg/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://schemastore.azurewebsites.net/schemas/json/sarif-2.1.0-rtm.5.json"
+
+	// sarifFingerprint is the partial fingerprint identifying a finding across scans
+	sarifFingerprint = "nemesisFinding/v1"
+)
+
+var (
+	// sarifLevels maps catalogue severities to SARIF result levels
+	sarifLevels = map[string]string{
+		catalogue.Critical: "error",
+		catalogue.High:     "error",
+		catalogue.Medium:   "warning",
+		catalogue.Low:      "note",
+	}
+
+	// sarifSecurityScores maps catalogue severities to the security-severity scores that code scanning dashboards rank findings by
+	sarifSecurityScores = map[string]string{
+		catalogue.Critical: "9.5",
+		catalogue.High:     "7.5",
+		catalogue.Medium:   "5.0",
+		catalogue.Low:      "2.0",
+	}
+)
+
+// SarifReporter is a reporter that writes audit reports to a SARIF 2.1.0 log file
+type SarifReporter struct {
+	path string
+}
+
+// NewSarifReporter returns a new SarifReporter writing to the given file path
+func NewSarifReporter(path string) *SarifReporter {
+	r := new(SarifReporter)
+	r.path = path
+	return r
+}
+
+// Publish writes the failed and waived controls of the reports to the SARIF log file
+func (r *SarifReporter) Publish(reports []Report) error {
+	b, err := json.MarshalIndent(newSarifLog(reports), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to render SARIF log: %v", err)
+	}
+	return ioutil.WriteFile(r.path, b, 0644)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool               `json:"tool"`
+	AutomationDetails *sarifAutomationDetails `json:"automationDetails,omitempty"`
+	Invocations       []sarifInvocation       `json:"invocations,omitempty"`
+	LogicalLocations  []sarifLogicalLocation  `json:"logicalLocations"`
+	Results           []sarifResult           `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	FullDescription      *sarifMessage          `json:"fullDescription,omitempty"`
+	Help                 *sarifMessage          `json:"help,omitempty"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration     `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifAutomationDetails struct {
+	ID   string `json:"id,omitempty"`
+	GUID string `json:"guid,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	StartTimeUTC        string `json:"startTimeUtc,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+	Index              *int   `json:"index,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression     `json:"suppressions,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+// sarifRuleID returns the rule a control maps to, which is its catalogue ID
+func sarifRuleID(c *Control) string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return strings.SplitN(c.Title, "=", 2)[0]
+}
+
+// newSarifRule returns the rule describing a control. CIS controls are named after their recommendation in the CIS registry
+func newSarifRule(c *Control) sarifRule {
+	// Parameterized controls, such as exposedPort=tcp/22, share the rule named before the '='
+	name := strings.SplitN(c.Title, "=", 2)[0]
+	rule := sarifRule{
+		ID:                   sarifRuleID(c),
+		Name:                 name,
+		ShortDescription:     sarifMessage{Text: name},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(c.Severity)},
+		Properties: map[string]interface{}{
+			"tags": []string{"security"},
+		},
+	}
+
+	if rec := c.Recommendation(); rec != nil {
+		rule.Name = rec.Name
+		rule.ShortDescription.Text = rec.Format()
+		rule.Properties["cisId"] = rec.CisID
+		rule.Properties["level"] = rec.Level
+		rule.Properties["scored"] = rec.Scored
+		rule.Properties["tags"] = []string{"security", "cis"}
+	}
+	if c.Rationale != "" {
+		rule.FullDescription = &sarifMessage{Text: c.Rationale}
+	}
+	if c.Remediation != "" {
+		rule.Help = &sarifMessage{Text: c.Remediation}
+	}
+	if len(c.References) > 0 {
+		rule.HelpURI = c.References[len(c.References)-1]
+	}
+	if c.Severity != "" {
+		rule.Properties["severity"] = c.Severity
+		rule.Properties["security-severity"] = sarifSecurityScores[c.Severity]
+	}
+	return rule
+}
+
+// sarifLevel returns the SARIF level of a severity, treating controls without a severity as warnings
+func sarifLevel(severity string) string {
+	if level, ok := sarifLevels[severity]; ok {
+		return level
+	}
+	return "warning"
+}
+
+// sarifResourceName returns the fully qualified name of a report's resource
+func sarifResourceName(r *Report) string {
+	if r.ResourceName != "" {
+		return r.ResourceName
+	}
+	if r.ProjectID != "" {
+		return r.ProjectID + "/" + r.Resource
+	}
+	return r.Resource
+}
+
+// sarifFingerprintOf identifies a finding by its rule, control title and resource, so the same finding is matched
+// across scans even though the run ID and timestamp change. The title tells apart parameterized controls of a rule,
+// such as the exposed ports of an instance
+func sarifFingerprintOf(c *Control, r *Report) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{sarifRuleID(c), c.Title, r.Type, sarifResourceName(r)}, "\n")))
+	return hex.EncodeToString(h[:])
+}
+
+// newSarifLog maps controls to rules, resources to logical locations, and failed and waived controls to results.
+// Waived controls are reported as suppressed results
+func newSarifLog(reports []Report) sarifLog {
+
+	// Rules are sorted by ID, so the log is stable across scans
+	rulesByID := map[string]sarifRule{}
+	for i := range reports {
+		for j := range reports[i].Controls {
+			c := &reports[i].Controls[j]
+			if _, ok := rulesByID[sarifRuleID(c)]; !ok {
+				rulesByID[sarifRuleID(c)] = newSarifRule(c)
+			}
+		}
+	}
+	rules := make([]sarifRule, 0, len(rulesByID))
+	for _, rule := range rulesByID {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	ruleIndex := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		ruleIndex[rule.ID] = i
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "nemesis",
+			Version:        version.GetVersion().VersionNumber(),
+			InformationURI: "https://github.com/UnityTech/nemesis",
+			Rules:          rules,
+		}},
+		LogicalLocations: []sarifLogicalLocation{},
+		Results:          []sarifResult{},
+	}
+
+	locationIndex := map[string]int{}
+	for i := range reports {
+		r := &reports[i]
+
+		if run.AutomationDetails == nil && r.RunID != "" {
+			run.AutomationDetails = &sarifAutomationDetails{ID: "nemesis/" + r.RunID, GUID: r.RunID}
+			run.Invocations = []sarifInvocation{{ExecutionSuccessful: true, StartTimeUTC: r.Timestamp.UTC().Format("2006-01-02T15:04:05Z")}}
+		}
+
+		name := sarifResourceName(r)
+		index, ok := locationIndex[name]
+		if !ok {
+			index = len(run.LogicalLocations)
+			locationIndex[name] = index
+			run.LogicalLocations = append(run.LogicalLocations, sarifLogicalLocation{
+				Name:               r.Resource,
+				FullyQualifiedName: name,
+				Kind:               r.Type,
+			})
+		}
+
+		for j := range r.Controls {
+			c := &r.Controls[j]
+			if c.Status != Failed && c.Status != Waived {
+				continue
+			}
+
+			ruleID := sarifRuleID(c)
+			message := c.Error
+			if message == "" {
+				message = c.Desc
+			}
+
+			result := sarifResult{
+				RuleID:    ruleID,
+				RuleIndex: ruleIndex[ruleID],
+				Level:     sarifLevel(c.Severity),
+				Message:   sarifMessage{Text: message},
+				Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+					FullyQualifiedName: name,
+					Index:              &index,
+				}}}},
+				PartialFingerprints: map[string]string{sarifFingerprint: sarifFingerprintOf(c, r)},
+				Properties: map[string]interface{}{
+					"title": r.Title,
+				},
+			}
+			if r.ProjectID != "" {
+				result.Properties["projectId"] = r.ProjectID
+			}
+			if c.Gcloud != "" {
+				result.Properties["gcloud"] = c.Gcloud
+			}
+			if c.Status == Waived {
+				result.Suppressions = []sarifSuppression{{Kind: "external", Status: "accepted", Justification: c.Detail}}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	return sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSarifReporter(t *testing.T) {
+
+	bucket := NewReport("storage_bucket", "Project my-project Storage Bucket my-bucket")
+	bucket.ProjectID = "my-project"
+	bucket.Resource = "my-bucket"
+	bucket.ResourceName = "//storage.googleapis.com/projects/_/buckets/my-bucket"
+	bucket.RunID = "2c5ea4c0-4067-4b1b-8d2b-8b6d1c0a3e7f"
+	bucket.Timestamp = time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+	public := NewCISControl("5.1", "Bucket should not be public")
+	public.Error = "Bucket my-bucket allows allUsers"
+	logging := NewCISControl("5.3", "Bucket should have access logging enabled")
+	logging.Passed()
+	bucket.AddControls(public, logging)
+
+	instance := NewReport("instance_exposure", "Project my-project Compute Instance my-instance Internet Exposure")
+	instance.ProjectID = "my-project"
+	instance.Resource = "my-instance"
+	ssh := NewControl("exposedPort=tcp/22", "Compute Instance should not be reachable from the internet on tcp/22")
+	redis := NewControl("exposedPort=tcp/6379", "Compute Instance should not be reachable from the internet on tcp/6379")
+	redis.Status = Waived
+	redis.Detail = "Waived by platform@example.com until 2019-12-31: Migration in progress"
+	instance.AddControls(ssh, redis)
+
+	path := filepath.Join(os.TempDir(), "nemesis-test.sarif")
+	defer os.Remove(path)
+	assert.Nil(t, NewSarifReporter(path).Publish([]Report{bucket, instance}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal(b, &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	run := log.Runs[0]
+	assert.Equal(t, "nemesis", run.Tool.Driver.Name)
+	assert.Equal(t, "2c5ea4c0-4067-4b1b-8d2b-8b6d1c0a3e7f", run.AutomationDetails.GUID)
+	assert.Equal(t, "2019-08-01T12:00:00Z", run.Invocations[0].StartTimeUTC)
+
+	// Every control maps to a rule, parameterized controls share a rule
+	rules := []string{}
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	assert.Equal(t, []string{"cis-5.1", "cis-5.3", "compute-exposed-port"}, rules)
+	cisRule := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "Ensure that Cloud Storage bucket is not anonymously or publicly accessible", cisRule.Name)
+	assert.Equal(t, "5.1", cisRule.Properties["cisId"])
+	assert.Equal(t, float64(1), cisRule.Properties["level"])
+	assert.Equal(t, "error", cisRule.DefaultConfiguration.Level)
+
+	// Each resource is a logical location
+	assert.Len(t, run.LogicalLocations, 2)
+	assert.Equal(t, "//storage.googleapis.com/projects/_/buckets/my-bucket", run.LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, "my-project/my-instance", run.LogicalLocations[1].FullyQualifiedName)
+
+	// Failed controls are results, waived controls are suppressed results
+	assert.Len(t, run.Results, 3)
+	assert.Equal(t, "cis-5.1", run.Results[0].RuleID)
+	assert.Equal(t, 0, run.Results[0].RuleIndex)
+	assert.Equal(t, "Bucket my-bucket allows allUsers", run.Results[0].Message.Text)
+	assert.Equal(t, 0, *run.Results[0].Locations[0].LogicalLocations[0].Index)
+	assert.Empty(t, run.Results[0].Suppressions)
+
+	assert.Equal(t, "compute-exposed-port", run.Results[1].RuleID)
+	assert.Equal(t, 2, run.Results[1].RuleIndex)
+	assert.Equal(t, 1, *run.Results[1].Locations[0].LogicalLocations[0].Index)
+	assert.Equal(t, "accepted", run.Results[2].Suppressions[0].Status)
+
+	// Findings on the same resource have distinct fingerprints
+	assert.NotEqual(t, run.Results[1].PartialFingerprints[sarifFingerprint], run.Results[2].PartialFingerprints[sarifFingerprint])
+}
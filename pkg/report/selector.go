@@ -0,0 +1,146 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/UnityTech/nemesis/pkg/catalogue"
+	"github.com/UnityTech/nemesis/pkg/cis"
+)
+
+var (
+	cisIDPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+	levelPattern = regexp.MustCompile(`^level(\d+)$`)
+
+	// ReportTypes lists the types of reports generated by an audit
+	ReportTypes = []string{
+		"api_key",
+		"collection_error",
+		"compute_address",
+		"compute_firewall_rule",
+		"compute_instance",
+		"compute_metadata",
+		"compute_network",
+		"compute_subnetwork",
+		"container_cluster",
+		"container_nodepool",
+		"dns_managed_zone",
+		"iam_policy",
+		"instance_exposure",
+		"kms_crypto_key",
+		"logging_configuration",
+		"org_policy",
+		"sql_instance",
+		"storage_bucket",
+	}
+)
+
+// isReportType returns whether a name is one of the types of reports generated by an audit
+func isReportType(name string) bool {
+	for _, typ := range ReportTypes {
+		if typ == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorTerm matches controls by CIS ID, CIS level, scoring, report type or control title
+type selectorTerm struct {
+	cisID  string
+	level  int
+	scored *bool
+	name   string
+}
+
+func (t selectorTerm) matches(typ string, c Control) bool {
+	rec := c.Recommendation()
+	switch {
+	case t.cisID != "":
+		return rec != nil && rec.CisID == t.cisID
+	case t.level != 0:
+		return rec != nil && rec.Level == t.level
+	case t.scored != nil:
+		return rec != nil && rec.Scored == *t.scored
+	default:
+		return t.name == typ || t.name == c.Title
+	}
+}
+
+// parseSelectorTerm parses a term formatted as a CIS ID (4.6), a CIS level (level1), scored, unscored,
+// a report type (compute_instance) or the title of a control that is not a CIS control (hasNatIP=false)
+func parseSelectorTerm(term string) (selectorTerm, error) {
+	scored, unscored := true, false
+
+	switch lower := strings.ToLower(term); {
+	case cisIDPattern.MatchString(term):
+		if _, ok := cis.Registry[term]; !ok {
+			return selectorTerm{}, fmt.Errorf("Unknown CIS recommendation '%v'", term)
+		}
+		return selectorTerm{cisID: term}, nil
+	case levelPattern.MatchString(lower):
+		level, _ := strconv.Atoi(levelPattern.FindStringSubmatch(lower)[1])
+		return selectorTerm{level: level}, nil
+	case lower == "scored":
+		return selectorTerm{scored: &scored}, nil
+	case lower == "unscored" || lower == "not-scored":
+		return selectorTerm{scored: &unscored}, nil
+	default:
+		if _, ok := catalogue.Lookup(term); !ok && !isReportType(term) {
+			return selectorTerm{}, fmt.Errorf("Unknown control selector '%v', expected a CIS ID, levelN, scored, unscored, a report type or a control title", term)
+		}
+		return selectorTerm{name: term}, nil
+	}
+}
+
+// Selector decides which controls are evaluated during an audit
+type Selector struct {
+	include []selectorTerm
+	exclude []selectorTerm
+}
+
+// NewSelector returns a selector for the given include and exclude terms. When no include terms are given,
+// every control is included. Exclude terms take precedence over include terms
+func NewSelector(include []string, exclude []string) (*Selector, error) {
+	s := new(Selector)
+	for _, term := range include {
+		t, err := parseSelectorTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		s.include = append(s.include, t)
+	}
+	for _, term := range exclude {
+		t, err := parseSelectorTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		s.exclude = append(s.exclude, t)
+	}
+	return s, nil
+}
+
+// Selects returns whether a control of a report of the given type should be evaluated
+func (s *Selector) Selects(typ string, c Control) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, t := range s.exclude {
+		if t.matches(typ, c) {
+			return false
+		}
+	}
+
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, t := range s.include {
+		if t.matches(typ, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelector(t *testing.T) {
+
+	level1 := NewCISControl("4.5", "IP forwarding should be disabled")
+	level2 := NewCISControl("4.6", "Disks should be encrypted with CSEK")
+	unscored := NewCISControl("4.2", "Project-wide SSH keys should be blocked")
+	custom := NewControl("hasNatIP=false", "Compute Instance should not have a NAT ip configured")
+
+	// Every control is selected by default
+	s, err := NewSelector(nil, nil)
+	assert.Nil(t, err)
+	for _, c := range []Control{level1, level2, unscored, custom} {
+		assert.True(t, s.Selects("compute_instance", c), c.Title)
+	}
+
+	s, err = NewSelector([]string{"level1"}, []string{"4.5"})
+	assert.Nil(t, err)
+	assert.False(t, s.Selects("compute_instance", level1))
+	assert.False(t, s.Selects("compute_instance", level2))
+	assert.True(t, s.Selects("compute_instance", unscored))
+	assert.False(t, s.Selects("compute_instance", custom))
+
+	s, err = NewSelector([]string{"scored", "hasNatIP=false"}, nil)
+	assert.Nil(t, err)
+	assert.True(t, s.Selects("compute_instance", level2))
+	assert.False(t, s.Selects("compute_instance", unscored))
+	assert.True(t, s.Selects("compute_instance", custom))
+
+	s, err = NewSelector(nil, []string{"compute_instance"})
+	assert.Nil(t, err)
+	assert.False(t, s.Selects("compute_instance", level1))
+	assert.True(t, s.Selects("compute_project_metadata", level1))
+
+	// Unknown CIS recommendations are rejected
+	_, err = NewSelector([]string{"4.60"}, nil)
+	assert.NotNil(t, err)
+
+	// Misspelled levels, report types and control titles are rejected
+	for _, term := range []string{"levl1", "compute_instances", "hasNatIp=false"} {
+		_, err = NewSelector(nil, []string{term})
+		assert.NotNil(t, err, term)
+	}
+}
+
+func TestAddControlsSkipsUnselectedControls(t *testing.T) {
+
+	s, err := NewSelector(nil, []string{"level2"})
+	assert.Nil(t, err)
+
+	r := NewReport("compute_instance", "Compute Instance my-instance")
+	r.SelectControls(s)
+	r.AddControls(NewCISControl("4.5", "IP forwarding should be disabled"), NewCISControl("4.6", "Disks should be encrypted with CSEK"))
+
+	assert.Equal(t, Failed, r.Controls[0].Status)
+	assert.Equal(t, Skipped, r.Controls[1].Status)
+
+	r.Controls[0].Passed()
+	assert.Equal(t, Passed, r.Status())
+}
@@ -3,25 +3,242 @@ package report
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/golang/glog"
 )
 
+const (
+	// FormatJSON prints all reports as a single JSON array
+	FormatJSON = "json"
+
+	// FormatJSONLines prints one JSON report per line
+	FormatJSONLines = "jsonl"
+
+	// FormatTable prints a summary of the controls and failing resources per project and report type as plain text tables
+	FormatTable = "table"
+
+	// FormatMarkdown prints the same summary as FormatTable as Markdown, to paste into tickets
+	FormatMarkdown = "markdown"
+)
+
+var (
+	// StdOutFormats lists the formats the StdOutReporter supports
+	StdOutFormats = []string{FormatJSON, FormatJSONLines, FormatTable, FormatMarkdown}
+
+	// summaryStatuses are the control statuses counted in summaries, in the order they are shown
+	summaryStatuses = []string{Passed, Failed, Waived, Skipped, NotApplicable}
+)
+
 // StdOutReporter is a reporter that prints audit reports to stdout
-type StdOutReporter struct{}
+type StdOutReporter struct {
+	format string
+	w      io.Writer
+}
 
-// NewStdOutReporter returns a new StdOutReporter for outputting the findings of an audit
-func NewStdOutReporter() *StdOutReporter {
+// NewStdOutReporter returns a new StdOutReporter for outputting the findings of an audit in the given format
+func NewStdOutReporter(format string) *StdOutReporter {
 	r := new(StdOutReporter)
+	r.format = format
+	r.w = os.Stdout
 	return r
 }
 
 // Publish prints a full list of reports to stdout
 func (r *StdOutReporter) Publish(reports []Report) error {
-	b, err := json.Marshal(&reports)
-	if err != nil {
-		glog.Fatalf("Failed to render report: %v", err)
+	switch r.format {
+	case FormatJSONLines:
+		enc := json.NewEncoder(r.w)
+		for i := range reports {
+			if err := enc.Encode(&reports[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatTable:
+		return writeTableSummary(r.w, summarize(reports))
+	case FormatMarkdown:
+		return writeMarkdownSummary(r.w, summarize(reports))
+	default:
+		b, err := json.Marshal(&reports)
+		if err != nil {
+			glog.Fatalf("Failed to render report: %v", err)
+		}
+		_, err = fmt.Fprintln(r.w, string(b))
+		return err
 	}
-	fmt.Println(string(b))
-	return nil
+}
+
+// controlSummary counts the statuses of a control across the resources of a report type
+type controlSummary struct {
+	title  string
+	counts map[string]int
+}
+
+// failingResource is a resource that failed a control
+type failingResource struct {
+	resource string
+	control  string
+	err      string
+}
+
+// reportSummary summarizes the reports of a report type within a project
+type reportSummary struct {
+	project  string
+	typ      string
+	controls []*controlSummary
+	failures []failingResource
+}
+
+// summary summarizes a set of reports, grouped by project and report type
+type summary struct {
+	reports int
+	counts  map[string]int
+	groups  []*reportSummary
+}
+
+// summarize groups reports by project and report type, counting control statuses and listing failing resources.
+// Groups are sorted by project and type, and controls are kept in the order they were first evaluated
+func summarize(reports []Report) *summary {
+	s := &summary{reports: len(reports), counts: map[string]int{}}
+	groups := map[string]*reportSummary{}
+
+	for i := range reports {
+		r := &reports[i]
+		project := r.ProjectID
+		if project == "" {
+			project = "(no project)"
+		}
+
+		key := project + "/" + r.Type
+		g, ok := groups[key]
+		if !ok {
+			g = &reportSummary{project: project, typ: r.Type}
+			groups[key] = g
+			s.groups = append(s.groups, g)
+		}
+
+		resource := r.Resource
+		if resource == "" {
+			resource = r.Title
+		}
+
+		for _, c := range r.Controls {
+			var cs *controlSummary
+			for _, existing := range g.controls {
+				if existing.title == c.Title {
+					cs = existing
+					break
+				}
+			}
+			if cs == nil {
+				cs = &controlSummary{title: c.Title, counts: map[string]int{}}
+				g.controls = append(g.controls, cs)
+			}
+			cs.counts[c.Status]++
+			s.counts[c.Status]++
+
+			if c.Status == Failed {
+				msg := c.Error
+				if msg == "" {
+					msg = c.Desc
+				}
+				g.failures = append(g.failures, failingResource{resource: resource, control: c.Title, err: msg})
+			}
+		}
+	}
+
+	sort.SliceStable(s.groups, func(i, j int) bool {
+		if s.groups[i].project != s.groups[j].project {
+			return s.groups[i].project < s.groups[j].project
+		}
+		return s.groups[i].typ < s.groups[j].typ
+	})
+	return s
+}
+
+// totals returns a one line description of the control statuses of the summary
+func (s *summary) totals() string {
+	controls := 0
+	parts := []string{}
+	for _, status := range summaryStatuses {
+		controls += s.counts[status]
+		parts = append(parts, fmt.Sprintf("%d %v", s.counts[status], status))
+	}
+	return fmt.Sprintf("%d reports, %d controls: %v", s.reports, controls, strings.Join(parts, ", "))
+}
+
+func writeTableSummary(out io.Writer, s *summary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, s.totals())
+
+	for _, g := range s.groups {
+		fmt.Fprintf(w, "\nProject %v - %v\n\n", g.project, g.typ)
+		fmt.Fprintln(w, "CONTROL\tPASSED\tFAILED\tWAIVED\tSKIPPED\tNOT APPLICABLE")
+		for _, c := range g.controls {
+			fmt.Fprintf(w, "%v", c.title)
+			for _, status := range summaryStatuses {
+				fmt.Fprintf(w, "\t%d", c.counts[status])
+			}
+			fmt.Fprintln(w)
+		}
+
+		if len(g.failures) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "RESOURCE\tCONTROL\tERROR")
+			for _, f := range g.failures {
+				fmt.Fprintf(w, "%v\t%v\t%v\n", f.resource, f.control, oneLine(f.err))
+			}
+		}
+
+		// Columns are aligned per group
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func writeMarkdownSummary(w io.Writer, s *summary) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# nemesis audit summary\n\n%v\n", s.totals())
+
+	for _, g := range s.groups {
+		fmt.Fprintf(&b, "\n## Project %v - %v\n\n", markdownCell(g.project), markdownCell(g.typ))
+		b.WriteString("| Control | Passed | Failed | Waived | Skipped | Not applicable |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+		for _, c := range g.controls {
+			fmt.Fprintf(&b, "| %v |", markdownCell(c.title))
+			for _, status := range summaryStatuses {
+				fmt.Fprintf(&b, " %d |", c.counts[status])
+			}
+			b.WriteString("\n")
+		}
+
+		if len(g.failures) > 0 {
+			b.WriteString("\n### Failing resources\n\n")
+			b.WriteString("| Resource | Control | Error |\n")
+			b.WriteString("|---|---|---|\n")
+			for _, f := range g.failures {
+				fmt.Fprintf(&b, "| %v | %v | %v |\n", markdownCell(f.resource), markdownCell(f.control), markdownCell(f.err))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// oneLine collapses a multi-line message onto a single line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownCell escapes a value so it can be placed in a Markdown table cell
+func markdownCell(s string) string {
+	return strings.Replace(oneLine(s), "|", "\\|", -1)
 }
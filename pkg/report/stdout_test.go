@@ -1 +1,94 @@
 package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStdOutReports() []Report {
+	bucket := NewReport("storage_bucket", "Project my-project Storage Bucket my-bucket")
+	bucket.ProjectID = "my-project"
+	bucket.Resource = "my-bucket"
+	public := NewCISControl("5.1", "Bucket should not be public")
+	public.Error = "Bucket my-bucket allows allUsers | allAuthenticatedUsers"
+	logging := NewCISControl("5.3", "Bucket should have access logging enabled")
+	logging.Passed()
+	bucket.AddControls(public, logging)
+
+	other := NewReport("storage_bucket", "Project my-project Storage Bucket other-bucket")
+	other.ProjectID = "my-project"
+	other.Resource = "other-bucket"
+	public = NewCISControl("5.1", "Bucket should not be public")
+	public.Passed()
+	logging = NewCISControl("5.3", "Bucket should have access logging enabled")
+	logging.Passed()
+	other.AddControls(public, logging)
+
+	instance := NewReport("compute_instance", "Project another-project Compute Instance my-instance")
+	instance.ProjectID = "another-project"
+	instance.Resource = "my-instance"
+	instance.AddControls(NewCISControl("4.1", "Instance should not use the default service account"))
+
+	return []Report{bucket, other, instance}
+}
+
+func TestStdOutReporterJSON(t *testing.T) {
+	reports := testStdOutReports()
+
+	var out bytes.Buffer
+	r := NewStdOutReporter(FormatJSON)
+	r.w = &out
+	assert.Nil(t, r.Publish(reports))
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+
+	var decoded []Report
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Len(t, decoded, 3)
+
+	// JSON lines print one report per line
+	out.Reset()
+	r = NewStdOutReporter(FormatJSONLines)
+	r.w = &out
+	assert.Nil(t, r.Publish(reports))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	var report Report
+	assert.Nil(t, json.Unmarshal([]byte(lines[2]), &report))
+	assert.Equal(t, "my-instance", report.Resource)
+}
+
+func TestStdOutReporterTable(t *testing.T) {
+
+	var out bytes.Buffer
+	r := NewStdOutReporter(FormatTable)
+	r.w = &out
+	assert.Nil(t, r.Publish(testStdOutReports()))
+	table := out.String()
+
+	assert.Contains(t, table, "3 reports, 5 controls: 3 passed, 2 failed, 0 waived, 0 skipped, 0 not_applicable")
+
+	// Groups are sorted by project and report type
+	assert.True(t, strings.Index(table, "Project another-project - compute_instance") < strings.Index(table, "Project my-project - storage_bucket"))
+	assert.Regexp(t, `CIS 5.1 - .* \(Scored\)\s+1\s+1\s+0\s+0\s+0`, table)
+	assert.Regexp(t, `my-bucket\s+CIS 5.1 - .*\s+Bucket my-bucket allows allUsers`, table)
+	assert.NotContains(t, table, "other-bucket")
+}
+
+func TestStdOutReporterMarkdown(t *testing.T) {
+
+	var out bytes.Buffer
+	r := NewStdOutReporter(FormatMarkdown)
+	r.w = &out
+	assert.Nil(t, r.Publish(testStdOutReports()))
+	md := out.String()
+
+	assert.Contains(t, md, "## Project my-project - storage_bucket")
+	assert.Contains(t, md, "| CIS 5.3 - Ensure that logging is enabled for Cloud storage buckets (Scored) | 2 | 0 | 0 | 0 | 0 |")
+
+	// Pipes in error messages are escaped, so they do not break the table
+	assert.Contains(t, md, "| my-bucket | CIS 5.1 - Ensure that Cloud Storage bucket is not anonymously or publicly accessible (Scored) | Bucket my-bucket allows allUsers \\| allAuthenticatedUsers |")
+}
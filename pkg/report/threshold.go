@@ -0,0 +1,91 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/UnityTech/nemesis/pkg/catalogue"
+)
+
+// Threshold decides whether the failed controls of an audit should fail the process running it, such as a CI pipeline
+type Threshold struct {
+	// Enable exits with a non-zero status when the threshold is exceeded
+	Enable bool `yaml:"enable" json:"enable"`
+
+	// Level only counts failed CIS controls up to the given CIS level. 0 counts controls of every level, including non-CIS controls
+	Level int `yaml:"level" json:"level"`
+
+	// Scored only counts failed scored CIS controls
+	Scored bool `yaml:"scored" json:"scored"`
+
+	// Severity only counts failed controls of at least the given severity
+	Severity string `yaml:"severity" json:"severity"`
+
+	// MaxFailures is the number of counted failed controls that are tolerated
+	MaxFailures int `yaml:"maxFailures" json:"maxFailures"`
+}
+
+// Validate checks that the threshold's level, severity and number of tolerated failures are valid
+func (t *Threshold) Validate() error {
+	problems := []string{}
+	if t.Level < 0 {
+		problems = append(problems, "level must not be negative")
+	}
+	if t.Severity != "" && catalogue.Rank(t.Severity) == 0 {
+		problems = append(problems, fmt.Sprintf("severity must be one of %v", strings.Join(catalogue.Severities, ", ")))
+	}
+	if t.MaxFailures < 0 {
+		problems = append(problems, "maxFailures must not be negative")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%v", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// Counts returns whether a control counts towards the threshold. Only failed controls count, so waived controls do not
+func (t *Threshold) Counts(c *Control) bool {
+	if c.Status != Failed {
+		return false
+	}
+
+	if t.Level > 0 || t.Scored {
+		rec := c.Recommendation()
+		if rec == nil {
+			return false
+		}
+		if t.Level > 0 && rec.Level > t.Level {
+			return false
+		}
+		if t.Scored && !rec.Scored {
+			return false
+		}
+	}
+
+	if t.Severity != "" && catalogue.Rank(c.Severity) < catalogue.Rank(t.Severity) {
+		return false
+	}
+	return true
+}
+
+// Check returns an error listing the counted failed controls when there are more than the threshold tolerates
+func (t *Threshold) Check(reports []Report) error {
+	if t == nil || !t.Enable {
+		return nil
+	}
+
+	failures := []string{}
+	for i := range reports {
+		for j := range reports[i].Controls {
+			c := &reports[i].Controls[j]
+			if t.Counts(c) {
+				failures = append(failures, fmt.Sprintf("%v: %v", reports[i].Title, c.Title))
+			}
+		}
+	}
+
+	if len(failures) > t.MaxFailures {
+		return fmt.Errorf("%d failed controls exceed the threshold of %d: %v", len(failures), t.MaxFailures, strings.Join(failures, "; "))
+	}
+	return nil
+}
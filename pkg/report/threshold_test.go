@@ -0,0 +1,46 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreshold(t *testing.T) {
+
+	r := NewReport("compute_instance", "Project my-project Compute Instance my-instance")
+	level1 := NewCISControl("4.1", "Instance should not use the default service account")
+	level2 := NewCISControl("4.6", "Instance disks should be encrypted with customer-supplied keys")
+	custom := NewControl("hasNatIP=false", "Compute Instance should not have a NAT ip configured")
+	waived := NewCISControl("4.5", "Instance should not forward IP packets")
+	waived.Status = Waived
+	passed := NewCISControl("4.4", "Instance should not enable serial port access")
+	passed.Passed()
+	r.AddControls(level1, level2, custom, waived, passed)
+	reports := []Report{r}
+
+	// A disabled threshold never fails
+	assert.Nil(t, (&Threshold{}).Check(reports))
+	assert.Nil(t, (*Threshold)(nil).Check(reports))
+
+	// Every failed control counts by default, but not waived ones
+	err := (&Threshold{Enable: true}).Check(reports)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "3 failed controls exceed the threshold of 0")
+	assert.Nil(t, (&Threshold{Enable: true, MaxFailures: 3}).Check(reports))
+
+	// Scored CIS controls at level 1
+	err = (&Threshold{Enable: true, Level: 1, Scored: true}).Check(reports)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 failed controls")
+	assert.Contains(t, err.Error(), "CIS 4.1")
+
+	// Severity
+	err = (&Threshold{Enable: true, Severity: "high"}).Check(reports)
+	assert.Contains(t, err.Error(), "1 failed controls")
+	assert.Nil(t, (&Threshold{Enable: true, Severity: "critical"}).Check(reports))
+
+	assert.NotNil(t, (&Threshold{Severity: "severe"}).Validate())
+	assert.NotNil(t, (&Threshold{MaxFailures: -1}).Validate())
+	assert.Nil(t, (&Threshold{Level: 2, Severity: "medium"}).Validate())
+}
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/UnityTech/nemesis/pkg/catalogue"
+	"github.com/UnityTech/nemesis/pkg/cis"
+)
+
+const (
+	// waiverDateFormat is the format of waiver expiry dates
+	waiverDateFormat = "2006-01-02"
+)
+
+// Waiver accepts the risk of a failing control for matching resources until it expires
+type Waiver struct {
+	// Project is a glob matching the project ID of the resource. Empty matches every project
+	Project string `yaml:"project" json:"project,omitempty"`
+
+	// Type is the report type of the resource. Empty matches every type
+	Type string `yaml:"type" json:"type,omitempty"`
+
+	// Resource is a glob matching the name of the resource. Empty matches every resource
+	Resource string `yaml:"resource" json:"resource,omitempty"`
+
+	// Control is the CIS ID of the control, or the title of a control that is not a CIS control
+	Control string `yaml:"control" json:"control"`
+
+	Justification string `yaml:"justification" json:"justification"`
+	Owner         string `yaml:"owner" json:"owner"`
+
+	// Expires is the date, formatted as YYYY-MM-DD, after which the waiver no longer applies
+	Expires string `yaml:"expires" json:"expires"`
+}
+
+// Validate returns an error if the waiver is missing mandatory fields or has invalid patterns
+func (w *Waiver) Validate() error {
+	problems := []string{}
+	if w.Control == "" {
+		problems = append(problems, "control is required")
+	} else if !isKnownControl(w.Control) {
+		problems = append(problems, fmt.Sprintf("unknown control '%v', expected a CIS ID or a control title", w.Control))
+	}
+	if w.Type != "" && !isReportType(w.Type) {
+		problems = append(problems, fmt.Sprintf("unknown report type '%v'", w.Type))
+	}
+	if w.Justification == "" {
+		problems = append(problems, "justification is required")
+	}
+	if w.Owner == "" {
+		problems = append(problems, "owner is required")
+	}
+	if _, err := w.Expiry(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	for _, pattern := range []string{w.Project, w.Resource} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid pattern '%v'", pattern))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("Waiver for control '%v': %v", w.Control, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// isKnownControl returns whether a control is the ID of a CIS recommendation or the title of a catalogued control
+func isKnownControl(control string) bool {
+	if _, ok := cis.Registry[control]; ok {
+		return true
+	}
+	_, ok := catalogue.Lookup(control)
+	return ok
+}
+
+// Expiry returns the time at which the waiver expires, which is the end of its expiry date
+func (w *Waiver) Expiry() (time.Time, error) {
+	if w.Expires == "" {
+		return time.Time{}, errors.New("expires is required")
+	}
+	t, err := time.Parse(waiverDateFormat, w.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expires '%v' must be formatted as YYYY-MM-DD", w.Expires)
+	}
+	return t.AddDate(0, 0, 1), nil
+}
+
+// IsExpired returns whether the waiver no longer applies at the given time
+func (w *Waiver) IsExpired(now time.Time) bool {
+	expiry, err := w.Expiry()
+	return err != nil || !now.Before(expiry)
+}
+
+// Matches returns whether the waiver applies to a control of a report
+func (w *Waiver) Matches(r *Report, c Control) bool {
+	if w.Type != "" && w.Type != r.Type {
+		return false
+	}
+	if !globMatches(w.Project, r.ProjectID) || !globMatches(w.Resource, r.Resource) {
+		return false
+	}
+	if rec := c.Recommendation(); rec != nil && rec.CisID == w.Control {
+		return true
+	}
+	return c.Title == w.Control
+}
+
+// globMatches returns whether a value matches a glob pattern. An empty pattern matches every value
+func globMatches(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// Waive marks a failed control as an accepted risk. Expired waivers leave the control failed, calling out the expiry
+func (c *Control) Waive(w *Waiver, now time.Time) {
+	if c.Status != Failed {
+		return
+	}
+
+	c.Waiver = w
+	if w.IsExpired(now) {
+		c.Detail = fmt.Sprintf("Waiver owned by %v expired on %v: %v", w.Owner, w.Expires, w.Justification)
+		return
+	}
+	c.Status = Waived
+	c.Detail = fmt.Sprintf("Waived by %v until %v: %v", w.Owner, w.Expires, w.Justification)
+}
+
+// HasExpiredWaiver returns whether the control failed because the waiver covering it expired
+func (c *Control) HasExpiredWaiver() bool {
+	return c.Waiver != nil && c.Status == Failed
+}
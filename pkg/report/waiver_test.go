@@ -0,0 +1,75 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaiverValidate(t *testing.T) {
+
+	w := Waiver{Control: "5.1", Justification: "Website assets", Owner: "web-team@example.com", Expires: "2030-01-31"}
+	assert.Nil(t, w.Validate())
+
+	w = Waiver{Control: "5.1", Expires: "31/01/2030", Resource: "[web"}
+	err := w.Validate()
+	assert.NotNil(t, err)
+	for _, problem := range []string{"justification", "owner", "YYYY-MM-DD", "[web"} {
+		assert.Contains(t, err.Error(), problem)
+	}
+
+	// Mistyped controls and report types would never match, so they are rejected
+	w = Waiver{Control: "5.l", Type: "storage_buckets", Justification: "Website assets", Owner: "web-team@example.com", Expires: "2030-01-31"}
+	err = w.Validate()
+	assert.NotNil(t, err)
+	for _, problem := range []string{"unknown control '5.l'", "unknown report type 'storage_buckets'"} {
+		assert.Contains(t, err.Error(), problem)
+	}
+
+	// Controls that are not CIS controls are waived by their title
+	w = Waiver{Control: "hasNatIP=true", Justification: "Bastion host", Owner: "platform@example.com", Expires: "2030-01-31"}
+	assert.Nil(t, w.Validate())
+}
+
+func TestWaiverIsExpired(t *testing.T) {
+
+	w := Waiver{Expires: "2030-01-31"}
+	assert.False(t, w.IsExpired(time.Date(2030, 1, 31, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, w.IsExpired(time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestAddControlsWaivesControls(t *testing.T) {
+
+	r := NewReport("storage_bucket", "Project my-site Storage Bucket www-assets")
+	r.ProjectID = "my-site"
+	r.Resource = "www-assets"
+	r.WaiveControls([]Waiver{
+		{Project: "my-*", Type: "storage_bucket", Resource: "www-*", Control: "5.1", Justification: "Website assets", Owner: "web-team@example.com", Expires: "2000-01-01"},
+		{Project: "my-*", Type: "storage_bucket", Resource: "www-*", Control: "5.1", Justification: "Website assets", Owner: "web-team@example.com", Expires: "2999-01-01"},
+		{Control: "5.3", Justification: "Access logs are not needed", Owner: "web-team@example.com", Expires: "2000-01-01"},
+		{Resource: "other-*", Control: "bucketPolicyOnly", Justification: "Other buckets", Owner: "web-team@example.com", Expires: "2999-01-01"},
+	})
+
+	public := NewCISControl("5.1", "Bucket should not be public")
+	logging := NewCISControl("5.3", "Bucket should have access logging enabled")
+	policyOnly := NewControl("bucketPolicyOnly", "Bucket Policy Only should be enabled")
+	r.AddControls(public, logging, policyOnly)
+
+	// Active waivers are preferred over expired ones
+	assert.Equal(t, Waived, r.Controls[0].Status)
+	assert.Equal(t, "2999-01-01", r.Controls[0].Waiver.Expires)
+	assert.False(t, r.Controls[0].HasExpiredWaiver())
+
+	// Expired waivers leave the control failed and are called out
+	assert.Equal(t, Failed, r.Controls[1].Status)
+	assert.True(t, r.Controls[1].HasExpiredWaiver())
+	assert.Contains(t, r.Controls[1].Detail, "expired on 2000-01-01")
+
+	// Waivers for other resources do not apply
+	assert.Equal(t, Failed, r.Controls[2].Status)
+	assert.Nil(t, r.Controls[2].Waiver)
+
+	r.Controls = r.Controls[:1]
+	assert.Equal(t, Passed, r.Status())
+}
@@ -0,0 +1,154 @@
+package gcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// An API target for this service allows the key to call every Google Cloud API
+	apiKeyAllAPIsService = "cloudapis.googleapis.com"
+)
+
+// ApiKey is an API key as returned by the API Keys service (apikeys.googleapis.com/v2).
+// The Google API client library does not ship an API Keys client, so the fields we audit are modelled here
+type ApiKey struct {
+	Name         string              `json:"name,omitempty"`
+	Uid          string              `json:"uid,omitempty"`
+	DisplayName  string              `json:"displayName,omitempty"`
+	CreateTime   string              `json:"createTime,omitempty"`
+	UpdateTime   string              `json:"updateTime,omitempty"`
+	Restrictions *ApiKeyRestrictions `json:"restrictions,omitempty"`
+}
+
+// ApiKeyRestrictions describes the restrictions placed on an API key
+type ApiKeyRestrictions struct {
+	BrowserKeyRestrictions *struct {
+		AllowedReferrers []string `json:"allowedReferrers,omitempty"`
+	} `json:"browserKeyRestrictions,omitempty"`
+	ServerKeyRestrictions *struct {
+		AllowedIps []string `json:"allowedIps,omitempty"`
+	} `json:"serverKeyRestrictions,omitempty"`
+	AndroidKeyRestrictions *struct {
+		AllowedApplications []struct {
+			PackageName     string `json:"packageName,omitempty"`
+			Sha1Fingerprint string `json:"sha1Fingerprint,omitempty"`
+		} `json:"allowedApplications,omitempty"`
+	} `json:"androidKeyRestrictions,omitempty"`
+	IosKeyRestrictions *struct {
+		AllowedBundleIds []string `json:"allowedBundleIds,omitempty"`
+	} `json:"iosKeyRestrictions,omitempty"`
+	ApiTargets []struct {
+		Service string   `json:"service,omitempty"`
+		Methods []string `json:"methods,omitempty"`
+	} `json:"apiTargets,omitempty"`
+}
+
+// ApiKeyResource represents a Google Cloud API key
+type ApiKeyResource struct {
+	k *ApiKey
+}
+
+// NewApiKeyResource returns a new ApiKeyResource
+func NewApiKeyResource(k *ApiKey) *ApiKeyResource {
+	r := new(ApiKeyResource)
+	r.k = k
+	return r
+}
+
+// Name returns the display name of the API key, falling back to its unique ID
+func (r *ApiKeyResource) Name() string {
+	if r.k.DisplayName != "" {
+		return r.k.DisplayName
+	}
+	return r.k.Uid
+}
+
+// ResourceName returns the full resource name of the API key
+func (r *ApiKeyResource) ResourceName() string {
+	if r.k.Name == "" {
+		return ""
+	}
+	return "//apikeys.googleapis.com/" + r.k.Name
+}
+
+// Marshal returns the underlying resource's JSON representation
+func (r *ApiKeyResource) Marshal() ([]byte, error) {
+	return json.Marshal(&r.k)
+}
+
+// Restrictions returns the restrictions placed on the API key
+func (r *ApiKeyResource) Restrictions() *ApiKeyRestrictions {
+	return r.k.Restrictions
+}
+
+// CreateTime returns when the API key was created
+func (r *ApiKeyResource) CreateTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, r.k.CreateTime)
+}
+
+// IsRestrictedToHostsAndApps returns an error when the API key can be used from any host or application
+func (r *ApiKeyResource) IsRestrictedToHostsAndApps() error {
+
+	restrictions := r.k.Restrictions
+	if restrictions == nil {
+		return errors.New("API key has no application restrictions")
+	}
+
+	if browser := restrictions.BrowserKeyRestrictions; browser != nil && len(browser.AllowedReferrers) > 0 {
+		for _, referrer := range browser.AllowedReferrers {
+			if referrer == "*" {
+				return fmt.Errorf("API key allows any HTTP referrer with %v", referrer)
+			}
+		}
+		return nil
+	}
+
+	if server := restrictions.ServerKeyRestrictions; server != nil && len(server.AllowedIps) > 0 {
+		for _, ip := range server.AllowedIps {
+			if ip == "0.0.0.0" || ip == "0.0.0.0/0" || ip == "::0" || ip == "::/0" {
+				return fmt.Errorf("API key allows any IP address with %v", ip)
+			}
+		}
+		return nil
+	}
+
+	if restrictions.AndroidKeyRestrictions != nil || restrictions.IosKeyRestrictions != nil {
+		return nil
+	}
+
+	return errors.New("API key has no application restrictions")
+}
+
+// IsRestrictedToAPIs returns an error when the API key can be used to call any API
+func (r *ApiKeyResource) IsRestrictedToAPIs() error {
+
+	if r.k.Restrictions == nil || len(r.k.Restrictions.ApiTargets) == 0 {
+		return errors.New("API key has no API restrictions")
+	}
+
+	for _, target := range r.k.Restrictions.ApiTargets {
+		if target.Service == apiKeyAllAPIsService {
+			return fmt.Errorf("API key allows access to all APIs through %v", apiKeyAllAPIsService)
+		}
+	}
+
+	return nil
+}
+
+// NeedsRotation returns an error when the API key is older than the given number of days
+func (r *ApiKeyResource) NeedsRotation(apiKeyExpirationTime int) error {
+
+	created, err := r.CreateTime()
+	if err != nil {
+		return fmt.Errorf("Failed to parse API key create time %v: %v", r.k.CreateTime, err)
+	}
+
+	if time.Since(created).Hours() > float64(apiKeyExpirationTime*24) {
+		return fmt.Errorf("API key is older than %v days", apiKeyExpirationTime)
+	}
+
+	return nil
+}
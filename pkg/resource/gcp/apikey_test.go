@@ -0,0 +1,71 @@
+package gcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper function for making fake API keys
+func makeTestApiKey(data []byte) *ApiKey {
+	key := new(ApiKey)
+	_ = json.Unmarshal(data, &key)
+	return key
+}
+
+var (
+	testRestrictedApiKeyData = []byte(`
+{
+	"name": "projects/01010101010101/locations/global/keys/abcdef",
+	"uid": "abcdef",
+	"displayName": "my-test-key",
+	"createTime": "2019-01-18T14:14:07.472Z",
+	"restrictions": {
+		"browserKeyRestrictions": {
+			"allowedReferrers": ["https://www.example.com/*"]
+		},
+		"apiTargets": [
+			{
+				"service": "maps-backend.googleapis.com"
+			}
+		]
+	}
+}
+`)
+
+	testRestrictedApiKey = makeTestApiKey(testRestrictedApiKeyData)
+)
+
+func TestApiKeyResourceRestrictions(t *testing.T) {
+
+	// A key restricted to a referrer and API passes
+	keyResource := NewApiKeyResource(testRestrictedApiKey)
+	assert.Equal(t, "my-test-key", keyResource.Name())
+	assert.Nil(t, keyResource.IsRestrictedToHostsAndApps())
+	assert.Nil(t, keyResource.IsRestrictedToAPIs())
+
+	// A key without restrictions fails both checks
+	keyResource = NewApiKeyResource(&ApiKey{Uid: "unrestricted"})
+	assert.Equal(t, "unrestricted", keyResource.Name())
+	assert.NotNil(t, keyResource.IsRestrictedToHostsAndApps())
+	assert.NotNil(t, keyResource.IsRestrictedToAPIs())
+
+	// Browser or server restrictions without any allowed referrers or IPs do not restrict the key
+	keyResource = NewApiKeyResource(makeTestApiKey([]byte(`{"restrictions": {"browserKeyRestrictions": {"allowedReferrers": []}}}`)))
+	assert.NotNil(t, keyResource.IsRestrictedToHostsAndApps())
+	keyResource = NewApiKeyResource(makeTestApiKey([]byte(`{"restrictions": {"serverKeyRestrictions": {"allowedIps": []}}}`)))
+	assert.NotNil(t, keyResource.IsRestrictedToHostsAndApps())
+}
+
+func TestApiKeyResourceNeedsRotation(t *testing.T) {
+
+	// An old key should be rotated
+	keyResource := NewApiKeyResource(testRestrictedApiKey)
+	assert.NotNil(t, keyResource.NeedsRotation(90))
+
+	// A new key does not need to be rotated
+	keyResource = NewApiKeyResource(&ApiKey{CreateTime: time.Now().Format(time.RFC3339)})
+	assert.Nil(t, keyResource.NeedsRotation(90))
+}
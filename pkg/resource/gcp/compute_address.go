@@ -28,6 +28,19 @@ func (r *ComputeAddressResource) Name() string {
 	return r.a.Name
 }
 
+// ResourceName returns the full resource name of the address
+func (r *ComputeAddressResource) ResourceName() string {
+	return selfLinkResourceName("compute.googleapis.com", r.a.SelfLink)
+}
+
+// Location returns the region of the address, or global for global addresses
+func (r *ComputeAddressResource) Location() string {
+	if r.a.Region == "" {
+		return GlobalLocation
+	}
+	return lastSegment(r.a.Region)
+}
+
 // Network returns the network the firewall rule resides within
 func (r *ComputeAddressResource) Network() string {
 	return r.a.Network
@@ -2,10 +2,145 @@ package gcp
 
 import (
 	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
 
 	compute "google.golang.org/api/compute/v1"
 )
 
+const (
+	firewallDirectionIngress = "INGRESS"
+	firewallProtocolAll      = "all"
+
+	// Source ranges at least this broad that reach outside of private address space
+	// are considered to be open to the internet, e.g. 0.0.0.0/1 or 2000::/3
+	internetPrefixLengthIPv4 = 8
+	internetPrefixLengthIPv6 = 16
+)
+
+var (
+	// Address space that is not routable on the internet
+	privateNetworks = mustParseCIDRs(
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+	)
+
+	// IANA protocol numbers that may be used in place of protocol names
+	protocolNumbers = map[string]string{
+		"1":   "icmp",
+		"6":   "tcp",
+		"17":  "udp",
+		"50":  "esp",
+		"51":  "ah",
+		"132": "sctp",
+	}
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := []*net.IPNet{}
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// parseCIDR parses a CIDR range, or a bare IP address as a single host range
+func parseCIDR(cidr string) (*net.IPNet, error) {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: cidr}
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, n, err := net.ParseCIDR(cidr)
+	return n, err
+}
+
+// cidrContains returns whether the network outer fully contains the network inner
+func cidrContains(outer *net.IPNet, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// isInternetRange returns whether a network is broad enough, and public enough, to be considered the internet
+func isInternetRange(n *net.IPNet) bool {
+	for _, private := range privateNetworks {
+		if cidrContains(private, n) {
+			return false
+		}
+	}
+
+	ones, bits := n.Mask.Size()
+	if bits == 32 {
+		return ones <= internetPrefixLengthIPv4
+	}
+	return ones <= internetPrefixLengthIPv6
+}
+
+// normalizeProtocol lowercases a protocol name and resolves protocol numbers to their names
+func normalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(protocol)
+	if name, ok := protocolNumbers[protocol]; ok {
+		return name
+	}
+	return protocol
+}
+
+// portsInclude returns whether a list of firewall ports or port ranges includes the given port.
+// An empty list of ports matches every port
+func portsInclude(ports []string, port int) bool {
+	if len(ports) == 0 {
+		return true
+	}
+
+	for _, p := range ports {
+		bounds := strings.SplitN(p, "-", 2)
+		low, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			continue
+		}
+		high := low
+		if len(bounds) == 2 {
+			if high, err = strconv.Atoi(bounds[1]); err != nil {
+				continue
+			}
+		}
+		if port >= low && port <= high {
+			return true
+		}
+	}
+
+	return false
+}
+
+// protocolPortMatches returns whether a rule's protocol and ports match the given protocol and port
+func protocolPortMatches(ruleProtocol string, rulePorts []string, protocol string, port int) bool {
+	ruleProtocol = normalizeProtocol(ruleProtocol)
+	if ruleProtocol != firewallProtocolAll && ruleProtocol != normalizeProtocol(protocol) {
+		return false
+	}
+	return portsInclude(rulePorts, port)
+}
+
 // ComputeFirewallRuleResource is a resource describing a Google Compute Firewall Rule
 type ComputeFirewallRuleResource struct {
 	f *compute.Firewall
@@ -28,31 +163,208 @@ func (r *ComputeFirewallRuleResource) Name() string {
 	return r.f.Name
 }
 
+// ResourceName returns the full resource name of the firewall rule
+func (r *ComputeFirewallRuleResource) ResourceName() string {
+	return selfLinkResourceName("compute.googleapis.com", r.f.SelfLink)
+}
+
 // Network returns the network the firewall rule resides within
 func (r *ComputeFirewallRuleResource) Network() string {
 	return r.f.Network
 }
 
-// AllowsSourceRange returns whether a given CIDR range is allowed by the firewall rule
+// Priority returns the priority of the firewall rule. Lower values take precedence
+func (r *ComputeFirewallRuleResource) Priority() int64 {
+	return r.f.Priority
+}
+
+// TargetTags returns the network tags of instances the rule applies to
+func (r *ComputeFirewallRuleResource) TargetTags() []string {
+	return r.f.TargetTags
+}
+
+// TargetServiceAccounts returns the service accounts of instances the rule applies to
+func (r *ComputeFirewallRuleResource) TargetServiceAccounts() []string {
+	return r.f.TargetServiceAccounts
+}
+
+// IsIngress returns whether the rule applies to incoming traffic
+func (r *ComputeFirewallRuleResource) IsIngress() bool {
+	return r.f.Direction == "" || strings.ToUpper(r.f.Direction) == firewallDirectionIngress
+}
+
+// IsDisabled returns whether the rule is disabled and therefore not enforced
+func (r *ComputeFirewallRuleResource) IsDisabled() bool {
+	return r.f.Disabled
+}
+
+// IsDeny returns whether the rule denies traffic rather than allowing it
+func (r *ComputeFirewallRuleResource) IsDeny() bool {
+	return len(r.f.Denied) > 0
+}
+
+// AppliesToAllInstances returns whether the rule applies to every instance in the network
+func (r *ComputeFirewallRuleResource) AppliesToAllInstances() bool {
+	return len(r.f.TargetTags) == 0 && len(r.f.TargetServiceAccounts) == 0
+}
+
+// AppliesToInstance returns whether the rule applies to an instance with the given network tags and service accounts
+func (r *ComputeFirewallRuleResource) AppliesToInstance(tags []string, serviceAccounts []string) bool {
+	if r.AppliesToAllInstances() {
+		return true
+	}
+
+	for _, t := range r.f.TargetTags {
+		for _, tag := range tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+
+	for _, sa := range r.f.TargetServiceAccounts {
+		for _, serviceAccount := range serviceAccounts {
+			if sa == serviceAccount {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sourceRanges returns the effective source ranges of an ingress rule. A rule that specifies no
+// source ranges, tags or service accounts applies to traffic from any address
+func (r *ComputeFirewallRuleResource) sourceRanges() []*net.IPNet {
+	ranges := r.f.SourceRanges
+	if len(ranges) == 0 && len(r.f.SourceTags) == 0 && len(r.f.SourceServiceAccounts) == 0 {
+		ranges = []string{"0.0.0.0/0"}
+	}
+
+	nets := []*net.IPNet{}
+	for _, s := range ranges {
+		if n, err := parseCIDR(s); err == nil {
+			nets = append(nets, n)
+		}
+	}
+	return nets
+}
+
+// AllowsSourceRange returns whether a given CIDR range is entirely covered by the firewall rule's source ranges
 func (r *ComputeFirewallRuleResource) AllowsSourceRange(sourceRange string) (result bool) {
-	for _, s := range r.f.SourceRanges {
-		if s == sourceRange {
+	target, err := parseCIDR(sourceRange)
+	if err != nil {
+		return
+	}
+
+	for _, n := range r.sourceRanges() {
+		if cidrContains(n, target) {
+			result = true
+			return
+		}
+	}
+	return
+}
+
+// AllowsInternetSource returns whether any of the firewall rule's source ranges are open to the internet
+func (r *ComputeFirewallRuleResource) AllowsInternetSource() (result bool) {
+	for _, n := range r.sourceRanges() {
+		if isInternetRange(n) {
 			result = true
+			return
 		}
 	}
 	return
 }
 
 // AllowsProtocolPort returns whether a given protocol:port combination is allowed by this firewall rule
-func (r *ComputeFirewallRuleResource) AllowsProtocolPort(protocol string, port string) (result bool) {
+func (r *ComputeFirewallRuleResource) AllowsProtocolPort(protocol string, port int) (result bool) {
 	for _, allowRule := range r.f.Allowed {
-		if allowRule.IPProtocol == protocol {
-			for _, p := range allowRule.Ports {
-				if port == p {
-					result = true
-				}
-			}
+		if protocolPortMatches(allowRule.IPProtocol, allowRule.Ports, protocol, port) {
+			result = true
+			return
+		}
+	}
+	return
+}
+
+// DeniesProtocolPort returns whether a given protocol:port combination is denied by this firewall rule
+func (r *ComputeFirewallRuleResource) DeniesProtocolPort(protocol string, port int) (result bool) {
+	for _, denyRule := range r.f.Denied {
+		if protocolPortMatches(denyRule.IPProtocol, denyRule.Ports, protocol, port) {
+			result = true
+			return
 		}
 	}
 	return
 }
+
+// ExposesPortToInternet returns whether the rule, on its own, allows traffic from the internet to the given protocol and port
+func (r *ComputeFirewallRuleResource) ExposesPortToInternet(protocol string, port int) bool {
+	return r.IsIngress() && !r.IsDisabled() && r.AllowsProtocolPort(protocol, port) && r.AllowsInternetSource()
+}
+
+// blocksInternetSourcesOf returns whether this deny rule blocks every internet source range that the other rule allows
+func (r *ComputeFirewallRuleResource) blocksInternetSourcesOf(other *ComputeFirewallRuleResource) bool {
+	denied := r.sourceRanges()
+	for _, n := range other.sourceRanges() {
+		if !isInternetRange(n) {
+			continue
+		}
+
+		covered := false
+		for _, d := range denied {
+			if cidrContains(d, n) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// coversTargetsOf returns whether this rule applies to every instance the other rule applies to
+func (r *ComputeFirewallRuleResource) coversTargetsOf(other *ComputeFirewallRuleResource) bool {
+	if r.AppliesToAllInstances() {
+		return true
+	}
+	if other.AppliesToAllInstances() {
+		return false
+	}
+
+	// Every instance targeted by the other rule must carry a tag or service account this rule targets
+	for _, t := range other.f.TargetTags {
+		if !r.AppliesToInstance([]string{t}, nil) {
+			return false
+		}
+	}
+	for _, sa := range other.f.TargetServiceAccounts {
+		if !r.AppliesToInstance(nil, []string{sa}) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsOverriddenBy returns whether traffic from the internet to the given protocol and port that this rule
+// allows is instead blocked by a deny rule of higher (or equal) priority in the same network
+func (r *ComputeFirewallRuleResource) IsOverriddenBy(rules []*ComputeFirewallRuleResource, protocol string, port int) bool {
+	for _, other := range rules {
+		if other == r || other.Network() != r.Network() || !other.IsIngress() || other.IsDisabled() {
+			continue
+		}
+
+		// Deny rules win over allow rules of the same priority
+		if other.Priority() > r.Priority() {
+			continue
+		}
+
+		if other.DeniesProtocolPort(protocol, port) && other.blocksInternetSourcesOf(r) && other.coversTargetsOf(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -1 +1,109 @@
 package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestComputeFirewallRuleResourceAllowsSourceRange(t *testing.T) {
+
+	rule := NewComputeFirewallRuleResource(&compute.Firewall{
+		Name:         "allow-office",
+		SourceRanges: []string{"10.0.0.0/8", "203.0.113.7"},
+	})
+
+	assert.True(t, rule.AllowsSourceRange("10.1.0.0/16"))
+	assert.True(t, rule.AllowsSourceRange("203.0.113.7/32"))
+	assert.False(t, rule.AllowsSourceRange("0.0.0.0/0"))
+	assert.False(t, rule.AllowsInternetSource())
+
+	// A rule without any sources applies to all traffic
+	rule = NewComputeFirewallRuleResource(&compute.Firewall{Name: "allow-all"})
+	assert.True(t, rule.AllowsSourceRange("0.0.0.0/0"))
+	assert.True(t, rule.AllowsInternetSource())
+
+	// Partial internet ranges are still considered open to the internet
+	for _, cidr := range []string{"0.0.0.0/0", "0.0.0.0/1", "::/0", "2000::/3"} {
+		rule = NewComputeFirewallRuleResource(&compute.Firewall{Name: "allow-internet", SourceRanges: []string{cidr}})
+		assert.True(t, rule.AllowsInternetSource(), cidr)
+	}
+}
+
+func TestComputeFirewallRuleResourceAllowsProtocolPort(t *testing.T) {
+
+	tests := []struct {
+		allowed  []*compute.FirewallAllowed
+		expected bool
+	}{
+		{[]*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}}, true},
+		{[]*compute.FirewallAllowed{{IPProtocol: "TCP", Ports: []string{"20-30"}}}, true},
+		{[]*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"0-65535"}}}, true},
+		{[]*compute.FirewallAllowed{{IPProtocol: "tcp"}}, true},
+		{[]*compute.FirewallAllowed{{IPProtocol: "6", Ports: []string{"22"}}}, true},
+		{[]*compute.FirewallAllowed{{IPProtocol: "all"}}, true},
+		{[]*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80", "443"}}}, false},
+		{[]*compute.FirewallAllowed{{IPProtocol: "udp", Ports: []string{"22"}}}, false},
+		{[]*compute.FirewallAllowed{{IPProtocol: "icmp"}}, false},
+	}
+
+	for _, test := range tests {
+		rule := NewComputeFirewallRuleResource(&compute.Firewall{Name: "rule", Allowed: test.allowed})
+		assert.Equal(t, test.expected, rule.AllowsProtocolPort("tcp", 22), "%+v", test.allowed[0])
+	}
+}
+
+func TestComputeFirewallRuleResourceExposesPortToInternet(t *testing.T) {
+
+	ssh := []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}}
+
+	rule := NewComputeFirewallRuleResource(&compute.Firewall{Name: "ssh", Allowed: ssh, SourceRanges: []string{"0.0.0.0/0"}})
+	assert.True(t, rule.ExposesPortToInternet("tcp", 22))
+	assert.False(t, rule.ExposesPortToInternet("tcp", 3389))
+
+	// Disabled and egress rules do not expose anything
+	rule = NewComputeFirewallRuleResource(&compute.Firewall{Name: "ssh", Allowed: ssh, SourceRanges: []string{"0.0.0.0/0"}, Disabled: true})
+	assert.False(t, rule.ExposesPortToInternet("tcp", 22))
+	rule = NewComputeFirewallRuleResource(&compute.Firewall{Name: "ssh", Allowed: ssh, DestinationRanges: []string{"0.0.0.0/0"}, Direction: "EGRESS"})
+	assert.False(t, rule.ExposesPortToInternet("tcp", 22))
+}
+
+func TestComputeFirewallRuleResourceIsOverriddenBy(t *testing.T) {
+
+	allow := NewComputeFirewallRuleResource(&compute.Firewall{
+		Name:         "allow-ssh",
+		Network:      "default",
+		Priority:     1000,
+		Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}},
+		SourceRanges: []string{"0.0.0.0/0"},
+		TargetTags:   []string{"bastion"},
+	})
+	deny := NewComputeFirewallRuleResource(&compute.Firewall{
+		Name:         "deny-all",
+		Network:      "default",
+		Priority:     900,
+		Denied:       []*compute.FirewallDenied{{IPProtocol: "all"}},
+		SourceRanges: []string{"0.0.0.0/0"},
+	})
+	lowPriorityDeny := NewComputeFirewallRuleResource(&compute.Firewall{
+		Name:         "deny-all-low",
+		Network:      "default",
+		Priority:     2000,
+		Denied:       []*compute.FirewallDenied{{IPProtocol: "all"}},
+		SourceRanges: []string{"0.0.0.0/0"},
+	})
+	otherTargetDeny := NewComputeFirewallRuleResource(&compute.Firewall{
+		Name:         "deny-web",
+		Network:      "default",
+		Priority:     900,
+		Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp"}},
+		SourceRanges: []string{"0.0.0.0/0"},
+		TargetTags:   []string{"web"},
+	})
+
+	assert.True(t, deny.IsDeny())
+	assert.True(t, allow.IsOverriddenBy([]*ComputeFirewallRuleResource{allow, deny}, "tcp", 22))
+	assert.False(t, allow.IsOverriddenBy([]*ComputeFirewallRuleResource{allow, lowPriorityDeny}, "tcp", 22))
+	assert.False(t, allow.IsOverriddenBy([]*ComputeFirewallRuleResource{allow, otherTargetDeny}, "tcp", 22))
+}
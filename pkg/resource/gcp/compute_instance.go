@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	compute "google.golang.org/api/compute/v1"
@@ -26,6 +27,21 @@ func (r *ComputeInstanceResource) Name() string {
 	return r.i.Name
 }
 
+// ResourceName returns the full resource name of the compute instance
+func (r *ComputeInstanceResource) ResourceName() string {
+	return selfLinkResourceName("compute.googleapis.com", r.i.SelfLink)
+}
+
+// Location returns the zone the compute instance resides in
+func (r *ComputeInstanceResource) Location() string {
+	return lastSegment(r.i.Zone)
+}
+
+// Labels returns the labels of the compute instance
+func (r *ComputeInstanceResource) Labels() map[string]string {
+	return r.i.Labels
+}
+
 // Marshal returns the underlying resource's JSON representation
 func (r *ComputeInstanceResource) Marshal() ([]byte, error) {
 	return json.Marshal(&r.i)
@@ -125,3 +141,77 @@ func (r *ComputeInstanceResource) UsesCustomerSuppliedEncryptionKeys() (err erro
 
 	return
 }
+
+// NetworkTags returns the network tags assigned to the instance
+func (r *ComputeInstanceResource) NetworkTags() []string {
+	if r.i.Tags == nil {
+		return []string{}
+	}
+	return r.i.Tags.Items
+}
+
+// ServiceAccountEmails returns the emails of the service accounts attached to the instance
+func (r *ComputeInstanceResource) ServiceAccountEmails() []string {
+	emails := []string{}
+	for _, sa := range r.i.ServiceAccounts {
+		emails = append(emails, sa.Email)
+	}
+	return emails
+}
+
+// ExternalIPs returns the external / NAT ips of the instance, keyed by the network of the interface they are attached to
+func (r *ComputeInstanceResource) ExternalIPs() map[string][]string {
+	ips := map[string][]string{}
+	for _, nic := range r.i.NetworkInterfaces {
+		for _, ac := range nic.AccessConfigs {
+			if ac.NatIP != "" {
+				ips[nic.Network] = append(ips[nic.Network], ac.NatIP)
+			}
+		}
+	}
+	return ips
+}
+
+// Exposure returns the ports of the instance that are reachable from the internet through its external ips,
+// given the firewall rules of the project the instance resides in
+func (r *ComputeInstanceResource) Exposure(rules []*ComputeFirewallRuleResource) *InstanceExposure {
+
+	e := &InstanceExposure{
+		Instance:        r.Name(),
+		ExternalIPs:     []string{},
+		Tags:            r.NetworkTags(),
+		ServiceAccounts: r.ServiceAccountEmails(),
+		ExposedPorts:    []ExposedPortRange{},
+	}
+
+	externalIPs := r.ExternalIPs()
+	networks := []string{}
+	for network, ips := range externalIPs {
+		networks = append(networks, network)
+		e.ExternalIPs = append(e.ExternalIPs, ips...)
+	}
+	sort.Strings(networks)
+	sort.Strings(e.ExternalIPs)
+
+	for _, network := range networks {
+
+		// Only rules on the interface's network that target this instance are relevant
+		applicable := []*ComputeFirewallRuleResource{}
+		for _, rule := range rules {
+			if rule.appliesToNetworkInstance(network, e.Tags, e.ServiceAccounts) {
+				applicable = append(applicable, rule)
+			}
+		}
+
+		for _, protocol := range portProtocols {
+			e.ExposedPorts = append(e.ExposedPorts, exposedPortRanges(applicable, protocol)...)
+		}
+		for _, protocol := range portlessProtocols {
+			if names := exposedBy(applicable, protocol, minPort); len(names) > 0 {
+				e.ExposedPorts = append(e.ExposedPorts, ExposedPortRange{Protocol: protocol, Rules: names})
+			}
+		}
+	}
+
+	return e
+}
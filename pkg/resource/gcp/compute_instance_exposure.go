@@ -0,0 +1,159 @@
+package gcp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	minPort = 0
+	maxPort = 65535
+)
+
+var (
+	// Protocols for which firewall rules may restrict ports
+	portProtocols = []string{"tcp", "udp", "sctp"}
+
+	// Protocols without ports that are reported when exposed through an "all" rule
+	portlessProtocols = []string{"icmp", "esp", "ah"}
+)
+
+// ExposedPortRange is a range of ports on a compute instance that is reachable from the internet
+type ExposedPortRange struct {
+	Protocol string   `json:"protocol"`
+	FromPort int      `json:"fromPort"`
+	ToPort   int      `json:"toPort"`
+	Rules    []string `json:"rules"`
+}
+
+// String returns the port range formatted as protocol/port or protocol/from-to
+func (p ExposedPortRange) String() string {
+	isPortProtocol := false
+	for _, protocol := range portProtocols {
+		if p.Protocol == protocol {
+			isPortProtocol = true
+		}
+	}
+	if !isPortProtocol {
+		return p.Protocol
+	}
+	if p.FromPort == p.ToPort {
+		return fmt.Sprintf("%v/%v", p.Protocol, p.FromPort)
+	}
+	return fmt.Sprintf("%v/%v-%v", p.Protocol, p.FromPort, p.ToPort)
+}
+
+// InstanceExposure describes how a compute instance can be reached from the internet
+type InstanceExposure struct {
+	Instance        string             `json:"instance"`
+	ExternalIPs     []string           `json:"externalIPs"`
+	Tags            []string           `json:"tags"`
+	ServiceAccounts []string           `json:"serviceAccounts"`
+	ExposedPorts    []ExposedPortRange `json:"exposedPorts"`
+}
+
+// portBoundaries returns the first port of every port range the rule's protocol entries use for a given protocol,
+// along with the port following the end of each range
+func (r *ComputeFirewallRuleResource) portBoundaries(protocol string) []int {
+	boundaries := []int{}
+
+	add := func(ruleProtocol string, ports []string) {
+		ruleProtocol = normalizeProtocol(ruleProtocol)
+		if ruleProtocol != firewallProtocolAll && ruleProtocol != protocol {
+			return
+		}
+		for _, p := range ports {
+			bounds := strings.SplitN(p, "-", 2)
+			low, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				continue
+			}
+			high := low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					continue
+				}
+			}
+			boundaries = append(boundaries, low, high+1)
+		}
+	}
+
+	for _, a := range r.f.Allowed {
+		add(a.IPProtocol, a.Ports)
+	}
+	for _, d := range r.f.Denied {
+		add(d.IPProtocol, d.Ports)
+	}
+	return boundaries
+}
+
+// appliesToNetworkInstance returns whether an enabled ingress rule applies to the instance on the given network
+func (r *ComputeFirewallRuleResource) appliesToNetworkInstance(network string, tags []string, serviceAccounts []string) bool {
+	return r.Network() == network && r.IsIngress() && !r.IsDisabled() && r.AppliesToInstance(tags, serviceAccounts)
+}
+
+// exposedBy returns the names of the allow rules through which the internet can reach the given protocol and port,
+// leaving out allow rules that are overridden by a deny rule. The rules all apply to the same instance, so a deny rule
+// overrides an allow rule regardless of which targets either rule covers
+func exposedBy(rules []*ComputeFirewallRuleResource, protocol string, port int) []string {
+	names := []string{}
+	for _, allow := range rules {
+		if allow.IsDeny() || !allow.AllowsInternetSource() || !allow.AllowsProtocolPort(protocol, port) {
+			continue
+		}
+
+		denied := false
+		for _, deny := range rules {
+			// Deny rules win over allow rules of the same priority
+			if deny.IsDeny() && deny.Priority() <= allow.Priority() && deny.DeniesProtocolPort(protocol, port) && deny.blocksInternetSourcesOf(allow) {
+				denied = true
+				break
+			}
+		}
+		if !denied {
+			names = append(names, allow.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exposedPortRanges returns the ranges of ports of a protocol that the given rules expose to the internet
+func exposedPortRanges(rules []*ComputeFirewallRuleResource, protocol string) []ExposedPortRange {
+
+	// Every port between two consecutive boundaries is treated identically by all rules, so only
+	// the first port of each segment needs to be evaluated
+	boundarySet := map[int]bool{minPort: true, maxPort + 1: true}
+	for _, r := range rules {
+		for _, b := range r.portBoundaries(protocol) {
+			if b >= minPort && b <= maxPort+1 {
+				boundarySet[b] = true
+			}
+		}
+	}
+	boundaries := []int{}
+	for b := range boundarySet {
+		boundaries = append(boundaries, b)
+	}
+	sort.Ints(boundaries)
+
+	ranges := []ExposedPortRange{}
+	for i := 0; i < len(boundaries)-1; i++ {
+		from, to := boundaries[i], boundaries[i+1]-1
+
+		names := exposedBy(rules, protocol, from)
+		if len(names) == 0 {
+			continue
+		}
+
+		// Merge with the previous range when they are contiguous and exposed by the same rules
+		if n := len(ranges); n > 0 && ranges[n-1].ToPort == from-1 && strings.Join(ranges[n-1].Rules, ",") == strings.Join(names, ",") {
+			ranges[n-1].ToPort = to
+			continue
+		}
+		ranges = append(ranges, ExposedPortRange{Protocol: protocol, FromPort: from, ToPort: to, Rules: names})
+	}
+	return ranges
+}
@@ -0,0 +1,135 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	compute "google.golang.org/api/compute/v1"
+)
+
+const testNetwork = "https://www.googleapis.com/compute/v1/projects/p/global/networks/default"
+
+func newTestInstance(natIP string, tags ...string) *ComputeInstanceResource {
+	nic := &compute.NetworkInterface{Network: testNetwork}
+	if natIP != "" {
+		nic.AccessConfigs = []*compute.AccessConfig{{NatIP: natIP}}
+	}
+	return NewComputeInstanceResource(&compute.Instance{
+		Name:              "instance",
+		Tags:              &compute.Tags{Items: tags},
+		NetworkInterfaces: []*compute.NetworkInterface{nic},
+		ServiceAccounts:   []*compute.ServiceAccount{{Email: "sa@p.iam.gserviceaccount.com"}},
+	})
+}
+
+func newTestRule(f *compute.Firewall) *ComputeFirewallRuleResource {
+	if f.Network == "" {
+		f.Network = testNetwork
+	}
+	if f.Priority == 0 {
+		f.Priority = 1000
+	}
+	return NewComputeFirewallRuleResource(f)
+}
+
+func exposedPortStrings(e *InstanceExposure) []string {
+	ports := []string{}
+	for _, p := range e.ExposedPorts {
+		ports = append(ports, p.String())
+	}
+	return ports
+}
+
+func TestComputeInstanceResourceExposure(t *testing.T) {
+
+	rules := []*ComputeFirewallRuleResource{
+		newTestRule(&compute.Firewall{
+			Name:         "allow-web",
+			SourceRanges: []string{"0.0.0.0/0"},
+			TargetTags:   []string{"web"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80", "443", "8000-8100"}}},
+		}),
+		newTestRule(&compute.Firewall{
+			Name:         "allow-internal",
+			SourceRanges: []string{"10.0.0.0/8"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "all"}},
+		}),
+		newTestRule(&compute.Firewall{
+			Name:         "allow-ssh",
+			SourceRanges: []string{"0.0.0.0/0"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}},
+		}),
+		newTestRule(&compute.Firewall{
+			Name:         "deny-debug",
+			Priority:     900,
+			SourceRanges: []string{"0.0.0.0/0"},
+			Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"8080"}}},
+		}),
+		newTestRule(&compute.Firewall{
+			Name:         "allow-dns-other-network",
+			Network:      "https://www.googleapis.com/compute/v1/projects/p/global/networks/other",
+			SourceRanges: []string{"0.0.0.0/0"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "udp", Ports: []string{"53"}}},
+		}),
+	}
+
+	// Only rules on the instance's network and targeting its tags apply. Denied ports split ranges
+	e := newTestInstance("203.0.113.10", "web").Exposure(rules)
+	assert.Equal(t, []string{"203.0.113.10"}, e.ExternalIPs)
+	assert.Equal(t, []string{"tcp/22", "tcp/80", "tcp/443", "tcp/8000-8079", "tcp/8081-8100"}, exposedPortStrings(e))
+	assert.Equal(t, []string{"allow-ssh"}, e.ExposedPorts[0].Rules)
+
+	// Instances without the target tag only see rules applying to all instances
+	e = newTestInstance("203.0.113.11").Exposure(rules)
+	assert.Equal(t, []string{"tcp/22"}, exposedPortStrings(e))
+
+	// Instances without an external ip are not reachable
+	e = newTestInstance("", "web").Exposure(rules)
+	assert.Empty(t, e.ExternalIPs)
+	assert.Empty(t, e.ExposedPorts)
+}
+
+func TestComputeInstanceResourceExposureTargetedDeny(t *testing.T) {
+
+	rules := []*ComputeFirewallRuleResource{
+		newTestRule(&compute.Firewall{
+			Name:         "allow-ssh",
+			SourceRanges: []string{"0.0.0.0/0"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}},
+		}),
+		newTestRule(&compute.Firewall{
+			Name:         "deny-ssh-web",
+			Priority:     900,
+			SourceRanges: []string{"0.0.0.0/0"},
+			TargetTags:   []string{"web"},
+			Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"22"}}},
+		}),
+	}
+
+	// A deny rule scoped to the instance's tag overrides an allow rule applying to all instances
+	e := newTestInstance("203.0.113.13", "web").Exposure(rules)
+	assert.Empty(t, e.ExposedPorts)
+
+	// Instances without the tag are still exposed
+	e = newTestInstance("203.0.113.14").Exposure(rules)
+	assert.Equal(t, []string{"tcp/22"}, exposedPortStrings(e))
+}
+
+func TestComputeInstanceResourceExposureAllProtocols(t *testing.T) {
+
+	rules := []*ComputeFirewallRuleResource{
+		newTestRule(&compute.Firewall{
+			Name:    "allow-everything",
+			Allowed: []*compute.FirewallAllowed{{IPProtocol: "all"}},
+		}),
+		newTestRule(&compute.Firewall{
+			Name:         "deny-udp",
+			Priority:     100,
+			SourceRanges: []string{"0.0.0.0/0"},
+			Denied:       []*compute.FirewallDenied{{IPProtocol: "udp"}},
+		}),
+	}
+
+	e := newTestInstance("203.0.113.12").Exposure(rules)
+	assert.Equal(t, []string{"tcp/0-65535", "sctp/0-65535", "icmp", "esp", "ah"}, exposedPortStrings(e))
+}
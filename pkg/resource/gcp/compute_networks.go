@@ -23,6 +23,11 @@ func (r *ComputeNetworkResource) Name() string {
 	return r.n.Name
 }
 
+// ResourceName returns the full resource name of the network
+func (r *ComputeNetworkResource) ResourceName() string {
+	return selfLinkResourceName("compute.googleapis.com", r.n.SelfLink)
+}
+
 // Marshal returns the underlying resource's JSON representation
 func (r *ComputeNetworkResource) Marshal() ([]byte, error) {
 	return json.Marshal(&r.n)
@@ -28,6 +28,16 @@ func (r *ComputeSubnetworkResource) Region() string {
 	return r.s.Region
 }
 
+// ResourceName returns the full resource name of the subnetwork
+func (r *ComputeSubnetworkResource) ResourceName() string {
+	return selfLinkResourceName("compute.googleapis.com", r.s.SelfLink)
+}
+
+// Location returns the name of the region the subnetwork resides in
+func (r *ComputeSubnetworkResource) Location() string {
+	return lastSegment(r.s.Region)
+}
+
 // Marshal returns the underlying resource's JSON representation
 func (r *ComputeSubnetworkResource) Marshal() ([]byte, error) {
 	return json.Marshal(&r.s)
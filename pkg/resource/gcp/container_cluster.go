@@ -3,6 +3,7 @@ package gcp
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	container "google.golang.org/api/container/v1"
 )
@@ -24,6 +25,11 @@ func NewContainerClusterResource(c *container.Cluster) *ContainerClusterResource
 	return r
 }
 
+// Labels returns the resource labels of the cluster
+func (r *ContainerClusterResource) Labels() map[string]string {
+	return r.c.ResourceLabels
+}
+
 // Marshal returns the underlying resource's JSON representation
 func (r *ContainerClusterResource) Marshal() ([]byte, error) {
 	return json.Marshal(&r.c)
@@ -34,6 +40,26 @@ func (r *ContainerClusterResource) Name() string {
 	return r.c.Name
 }
 
+// Location returns the zone or region the container cluster resides in
+func (r *ContainerClusterResource) Location() string {
+	return r.c.Location
+}
+
+// ResourceName returns the full resource name of the container cluster
+func (r *ContainerClusterResource) ResourceName() string {
+	// Self links address clusters by zone, while resource names address them by location
+	return strings.Replace(selfLinkResourceName("container.googleapis.com", r.c.SelfLink), "/zones/", "/locations/", 1)
+}
+
+// NodePools returns the nodepools that belong to the container cluster
+func (r *ContainerClusterResource) NodePools() []*ContainerNodePoolResource {
+	nodepools := []*ContainerNodePoolResource{}
+	for _, n := range r.c.NodePools {
+		nodepools = append(nodepools, NewContainerNodePoolResource(n, r))
+	}
+	return nodepools
+}
+
 // IsStackdriverLoggingEnabled indicates whether logging.googleapis.com is set as the logging service
 func (r *ContainerClusterResource) IsStackdriverLoggingEnabled() bool {
 	return r.c.LoggingService == loggingService
@@ -114,8 +140,8 @@ func (r *ContainerClusterResource) IsUsingDefaultServiceAccount() bool {
 	return r.c.NodeConfig.ServiceAccount == "default"
 }
 
-// IsUsingMinimalOAuthScopes returns whether the GKE cluster is using the defined minimal oauth scopes for a cluster
-func (r *ContainerClusterResource) IsUsingMinimalOAuthScopes() (result bool, err error) {
+// IsUsingMinimalOAuthScopes returns whether the GKE cluster is using only the given minimal oauth scopes
+func (r *ContainerClusterResource) IsUsingMinimalOAuthScopes(minimalOAuthScopes []string) (result bool, err error) {
 
 	// Begin with the assumption that we are using minimal oauth scopes
 	extraScopes := []string{}
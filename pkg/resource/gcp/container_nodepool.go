@@ -4,19 +4,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	container "google.golang.org/api/container/v1"
 )
 
 // ContainerNodePoolResource is a resource for testing information about a GKE Node Pool's configuration
 type ContainerNodePoolResource struct {
-	n *container.NodePool
+	n       *container.NodePool
+	cluster *ContainerClusterResource
 }
 
-// NewContainerNodePoolResource returns a new ContainerNodePoolResource
-func NewContainerNodePoolResource(n *container.NodePool) *ContainerNodePoolResource {
+// NewContainerNodePoolResource returns a new ContainerNodePoolResource that belongs to the given cluster
+func NewContainerNodePoolResource(n *container.NodePool, cluster *ContainerClusterResource) *ContainerNodePoolResource {
 	r := new(ContainerNodePoolResource)
 	r.n = n
+	r.cluster = cluster
 	return r
 }
 
@@ -30,14 +33,38 @@ func (r *ContainerNodePoolResource) Name() string {
 	return r.n.Name
 }
 
+// ResourceName returns the full resource name of the nodepool
+func (r *ContainerNodePoolResource) ResourceName() string {
+	return strings.Replace(selfLinkResourceName("container.googleapis.com", r.n.SelfLink), "/zones/", "/locations/", 1)
+}
+
+// ClusterName returns the name of the cluster the nodepool belongs to
+func (r *ContainerNodePoolResource) ClusterName() string {
+	if r.cluster == nil {
+		return ""
+	}
+	return r.cluster.Name()
+}
+
+// Location returns the zone or region of the cluster the nodepool belongs to
+func (r *ContainerNodePoolResource) Location() string {
+	if r.cluster == nil {
+		return ""
+	}
+	return r.cluster.Location()
+}
+
 // IsLegacyMetadataAPIDisabled returns whether the given Node Pool has legacy metadata APIs disabled
 func (r *ContainerNodePoolResource) IsLegacyMetadataAPIDisabled() (result bool, err error) {
-	var val string
-	var ok bool
-	if val, ok = r.n.Config.Metadata["disable-legacy-endpoints"]; !ok {
-		err = errors.New("Could not find key 'disable-legacy-endpoints'")
+	if r.n.Config == nil {
+		err = errors.New("Node pool does not define a node configuration")
+		return
 	}
-	if val != "true" {
+
+	val, ok := r.n.Config.Metadata["disable-legacy-endpoints"]
+	if !ok {
+		err = errors.New("Could not find key 'disable-legacy-endpoints'")
+	} else if val != "true" {
 		err = fmt.Errorf("Invalid value for `disable-legacy-endpoints`, got `%v'", val)
 	}
 	result = err == nil
@@ -46,19 +73,29 @@ func (r *ContainerNodePoolResource) IsLegacyMetadataAPIDisabled() (result bool,
 
 // IsAutoRepairEnabled returns whether a Node Pool is configured to automatically repair on error
 func (r *ContainerNodePoolResource) IsAutoRepairEnabled() bool {
+	if r.n.Management == nil {
+		return false
+	}
 	return r.n.Management.AutoRepair
 }
 
 // IsAutoUpgradeEnabled returns whether a Node Pool is configured to automatically upgrade GKE versions
 func (r *ContainerNodePoolResource) IsAutoUpgradeEnabled() bool {
+	if r.n.Management == nil {
+		return false
+	}
 	return r.n.Management.AutoUpgrade
 }
 
 // CheckDistributionTypeIs returns whether a Node Pool's OS distribution is the expected type
 func (r *ContainerNodePoolResource) CheckDistributionTypeIs(expected string) (result bool, err error) {
-	result = r.n.Config.ImageType == expected
+	imageType := ""
+	if r.n.Config != nil {
+		imageType = r.n.Config.ImageType
+	}
+	result = imageType == expected
 	if !result {
-		err = fmt.Errorf("Node pool is using %v, not %v", r.n.Config.ImageType, expected)
+		err = fmt.Errorf("Node pool is using %v, not %v", imageType, expected)
 	}
 	return
 }
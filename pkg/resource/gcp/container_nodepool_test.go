@@ -1 +1,89 @@
 package gcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	container "google.golang.org/api/container/v1"
+)
+
+// Helper function for making fake cluster resources
+func makeTestCluster(data []byte) *container.Cluster {
+	cluster := new(container.Cluster)
+	_ = json.Unmarshal(data, &cluster)
+	return cluster
+}
+
+var (
+	testValidClusterData = []byte(`
+{
+	"name": "my-test-cluster",
+	"location": "us-central1",
+	"nodePools": [
+		{
+			"name": "default-pool",
+			"config": {
+				"imageType": "COS",
+				"metadata": {
+					"disable-legacy-endpoints": "true"
+				}
+			},
+			"management": {
+				"autoRepair": true,
+				"autoUpgrade": true
+			}
+		},
+		{
+			"name": "legacy-pool",
+			"config": {
+				"imageType": "UBUNTU"
+			}
+		}
+	]
+}
+`)
+
+	testValidCluster = makeTestCluster(testValidClusterData)
+)
+
+func TestContainerClusterResourceNodePools(t *testing.T) {
+
+	clusterResource := NewContainerClusterResource(testValidCluster)
+	nodepools := clusterResource.NodePools()
+	assert.Len(t, nodepools, 2)
+
+	// Each nodepool should be linked back to its parent cluster
+	for _, n := range nodepools {
+		assert.Equal(t, "my-test-cluster", n.ClusterName())
+		assert.Equal(t, "us-central1", n.Location())
+	}
+}
+
+func TestContainerNodePoolResourceChecks(t *testing.T) {
+
+	nodepools := NewContainerClusterResource(testValidCluster).NodePools()
+
+	// The default pool should pass every check
+	defaultPool := nodepools[0]
+	assert.Equal(t, "default-pool", defaultPool.Name())
+	assert.True(t, defaultPool.IsAutoRepairEnabled())
+	assert.True(t, defaultPool.IsAutoUpgradeEnabled())
+	ok, err := defaultPool.IsLegacyMetadataAPIDisabled()
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	ok, err = defaultPool.CheckDistributionTypeIs("COS")
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	// The legacy pool has no management or metadata configured
+	legacyPool := nodepools[1]
+	assert.False(t, legacyPool.IsAutoRepairEnabled())
+	assert.False(t, legacyPool.IsAutoUpgradeEnabled())
+	ok, err = legacyPool.IsLegacyMetadataAPIDisabled()
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+	ok, err = legacyPool.CheckDistributionTypeIs("COS")
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dns "google.golang.org/api/dns/v1"
+)
+
+const (
+	dnssecStateOn       = "on"
+	dnssecKeySigning    = "keySigning"
+	dnssecZoneSigning   = "zoneSigning"
+	dnssecAlgorithmSHA1 = "rsasha1"
+	dnsVisibilityPublic = "public"
+)
+
+// DNSManagedZoneResource represents a Google Cloud DNS managed zone
+type DNSManagedZoneResource struct {
+	z *dns.ManagedZone
+}
+
+// NewDNSManagedZoneResource returns a new DNSManagedZoneResource
+func NewDNSManagedZoneResource(z *dns.ManagedZone) *DNSManagedZoneResource {
+	r := new(DNSManagedZoneResource)
+	r.z = z
+	return r
+}
+
+// Name returns the name of the managed zone
+func (r *DNSManagedZoneResource) Name() string {
+	return r.z.Name
+}
+
+// DNSName returns the DNS name served by the managed zone
+func (r *DNSManagedZoneResource) DNSName() string {
+	return r.z.DnsName
+}
+
+// Labels returns the labels of the managed zone
+func (r *DNSManagedZoneResource) Labels() map[string]string {
+	return r.z.Labels
+}
+
+// Marshal returns the underlying resource's JSON representation
+func (r *DNSManagedZoneResource) Marshal() ([]byte, error) {
+	return json.Marshal(&r.z)
+}
+
+// IsPrivate returns whether the managed zone is only visible to VPC networks.
+// An empty visibility is treated as public, which is the API default
+func (r *DNSManagedZoneResource) IsPrivate() bool {
+	return r.z.Visibility != "" && r.z.Visibility != dnsVisibilityPublic
+}
+
+// IsDNSSECEnabled returns whether DNSSEC is turned on for the managed zone
+func (r *DNSManagedZoneResource) IsDNSSECEnabled() bool {
+	if r.z.DnssecConfig == nil {
+		return false
+	}
+	return r.z.DnssecConfig.State == dnssecStateOn
+}
+
+// usesAlgorithmForKeyType returns an error if a key of the given type uses the given algorithm
+func (r *DNSManagedZoneResource) usesAlgorithmForKeyType(keyType string, algorithm string) (err error) {
+	if r.z.DnssecConfig == nil {
+		return
+	}
+
+	for _, spec := range r.z.DnssecConfig.DefaultKeySpecs {
+		if spec.KeyType == keyType && spec.Algorithm == algorithm {
+			err = fmt.Errorf("DNSSEC %v key uses %v", keyType, algorithm)
+			return
+		}
+	}
+
+	return
+}
+
+// KeySigningKeyUsesRSASHA1 returns an error when the key-signing key of the zone uses RSASHA1
+func (r *DNSManagedZoneResource) KeySigningKeyUsesRSASHA1() error {
+	return r.usesAlgorithmForKeyType(dnssecKeySigning, dnssecAlgorithmSHA1)
+}
+
+// ZoneSigningKeyUsesRSASHA1 returns an error when the zone-signing key of the zone uses RSASHA1
+func (r *DNSManagedZoneResource) ZoneSigningKeyUsesRSASHA1() error {
+	return r.usesAlgorithmForKeyType(dnssecZoneSigning, dnssecAlgorithmSHA1)
+}
@@ -0,0 +1,38 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dns "google.golang.org/api/dns/v1"
+)
+
+func TestDNSManagedZoneResourceDNSSEC(t *testing.T) {
+
+	// A public zone without DNSSEC configured
+	zone := NewDNSManagedZoneResource(&dns.ManagedZone{Name: "my-zone", Visibility: "public"})
+	assert.False(t, zone.IsPrivate())
+	assert.False(t, zone.IsDNSSECEnabled())
+	assert.Nil(t, zone.KeySigningKeyUsesRSASHA1())
+	assert.Nil(t, zone.ZoneSigningKeyUsesRSASHA1())
+
+	// A public zone with DNSSEC configured using RSASHA1 for its zone-signing key
+	zone = NewDNSManagedZoneResource(&dns.ManagedZone{
+		Name: "my-zone",
+		DnssecConfig: &dns.ManagedZoneDnsSecConfig{
+			State: "on",
+			DefaultKeySpecs: []*dns.DnsKeySpec{
+				{Algorithm: "rsasha256", KeyType: "keySigning"},
+				{Algorithm: "rsasha1", KeyType: "zoneSigning"},
+			},
+		},
+	})
+	assert.False(t, zone.IsPrivate())
+	assert.True(t, zone.IsDNSSECEnabled())
+	assert.Nil(t, zone.KeySigningKeyUsesRSASHA1())
+	assert.NotNil(t, zone.ZoneSigningKeyUsesRSASHA1())
+
+	// Private zones are identified by their visibility
+	zone = NewDNSManagedZoneResource(&dns.ManagedZone{Name: "my-zone", Visibility: "private"})
+	assert.True(t, zone.IsPrivate())
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"strings"
 
+	cloudkms "google.golang.org/api/cloudkms/v1"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
+	storage "google.golang.org/api/storage/v1"
 )
 
 const (
@@ -21,6 +24,9 @@ const (
 var (
 	// Cloud Audit log types
 	logTypes = []string{"ADMIN_READ", "DATA_READ", "DATA_WRITE"}
+
+	// Members that grant access to anyone on the internet
+	publicMembers = []string{"allUsers", "allAuthenticatedUsers"}
 )
 
 // Helper functions for identifying various types of users or roles
@@ -48,13 +54,214 @@ func NewIamPolicyResource(p *cloudresourcemanager.Policy) *IamPolicyResource {
 	return r
 }
 
+// NewIamPolicyResourceFromKMS returns a new IamPolicyResource from a Cloud KMS IAM policy.
+// A nil policy results in a policy without any bindings
+func NewIamPolicyResourceFromKMS(p *cloudkms.Policy) *IamPolicyResource {
+	policy := new(cloudresourcemanager.Policy)
+	if p != nil {
+		policy.Etag = p.Etag
+		policy.Version = p.Version
+		for _, b := range p.Bindings {
+			binding := &cloudresourcemanager.Binding{
+				Role:    b.Role,
+				Members: b.Members,
+			}
+			if b.Condition != nil {
+				binding.Condition = &cloudresourcemanager.Expr{
+					Title:       b.Condition.Title,
+					Description: b.Condition.Description,
+					Expression:  b.Condition.Expression,
+					Location:    b.Condition.Location,
+				}
+			}
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+	}
+	return NewIamPolicyResource(policy)
+}
+
+// NewIamPolicyResourceFromStorage returns a new IamPolicyResource from a Cloud Storage bucket IAM policy.
+// A nil policy results in a policy without any bindings
+func NewIamPolicyResourceFromStorage(p *storage.Policy) *IamPolicyResource {
+	policy := new(cloudresourcemanager.Policy)
+	if p != nil {
+		policy.Etag = p.Etag
+		for _, b := range p.Bindings {
+			binding := &cloudresourcemanager.Binding{
+				Role:    b.Role,
+				Members: b.Members,
+			}
+			if b.Condition != nil {
+				binding.Condition = &cloudresourcemanager.Expr{
+					Title:       b.Condition.Title,
+					Description: b.Condition.Description,
+					Expression:  b.Condition.Expression,
+					Location:    b.Condition.Location,
+				}
+			}
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+	}
+	return NewIamPolicyResource(policy)
+}
+
+// NewIamPolicyResourceFromFolder returns a new IamPolicyResource from a Resource Manager folder IAM policy.
+// A nil policy results in a policy without any bindings
+func NewIamPolicyResourceFromFolder(p *cloudresourcemanagerv2.Policy) *IamPolicyResource {
+	policy := new(cloudresourcemanager.Policy)
+	if p != nil {
+		policy.Etag = p.Etag
+		policy.Version = p.Version
+		for _, b := range p.Bindings {
+			binding := &cloudresourcemanager.Binding{
+				Role:    b.Role,
+				Members: b.Members,
+			}
+			if b.Condition != nil {
+				binding.Condition = &cloudresourcemanager.Expr{
+					Title:       b.Condition.Title,
+					Description: b.Condition.Description,
+					Expression:  b.Condition.Expression,
+					Location:    b.Condition.Location,
+				}
+			}
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+		for _, a := range p.AuditConfigs {
+			auditConfig := &cloudresourcemanager.AuditConfig{Service: a.Service}
+			for _, l := range a.AuditLogConfigs {
+				auditConfig.AuditLogConfigs = append(auditConfig.AuditLogConfigs, &cloudresourcemanager.AuditLogConfig{
+					LogType:         l.LogType,
+					ExemptedMembers: l.ExemptedMembers,
+				})
+			}
+			policy.AuditConfigs = append(policy.AuditConfigs, auditConfig)
+		}
+	}
+	return NewIamPolicyResource(policy)
+}
+
+// appendUnique appends the values that are not already present in a list
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, l := range list {
+			if v == l {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// bindingKey identifies the bindings that grant the same role under the same condition
+func bindingKey(b *cloudresourcemanager.Binding) string {
+	if b.Condition == nil {
+		return b.Role
+	}
+	return fmt.Sprintf("%v|%v", b.Role, b.Condition.Expression)
+}
+
+// MergeIamPolicyResources returns a new IamPolicyResource containing the bindings and audit configs of all given policies.
+// Members of bindings that share a role and condition are combined into a single binding, so conditional grants are never
+// merged into unconditional ones, and audit log configs are combined per service
+func MergeIamPolicyResources(policies ...*IamPolicyResource) *IamPolicyResource {
+
+	merged := new(cloudresourcemanager.Policy)
+	bindings := make(map[string]*cloudresourcemanager.Binding)
+	auditConfigs := make(map[string]*cloudresourcemanager.AuditConfig)
+
+	for _, policy := range policies {
+		if policy == nil || policy.p == nil {
+			continue
+		}
+
+		for _, b := range policy.p.Bindings {
+			key := bindingKey(b)
+			binding, ok := bindings[key]
+			if !ok {
+				binding = &cloudresourcemanager.Binding{Role: b.Role, Condition: b.Condition}
+				bindings[key] = binding
+				merged.Bindings = append(merged.Bindings, binding)
+			}
+
+			binding.Members = appendUnique(binding.Members, b.Members...)
+		}
+
+		for _, a := range policy.p.AuditConfigs {
+			auditConfig, ok := auditConfigs[a.Service]
+			if !ok {
+				auditConfig = &cloudresourcemanager.AuditConfig{Service: a.Service}
+				auditConfigs[a.Service] = auditConfig
+				merged.AuditConfigs = append(merged.AuditConfigs, auditConfig)
+			}
+
+			for _, l := range a.AuditLogConfigs {
+				var logConfig *cloudresourcemanager.AuditLogConfig
+				for _, existing := range auditConfig.AuditLogConfigs {
+					if existing.LogType == l.LogType {
+						logConfig = existing
+						break
+					}
+				}
+				if logConfig == nil {
+					logConfig = &cloudresourcemanager.AuditLogConfig{LogType: l.LogType}
+					auditConfig.AuditLogConfigs = append(auditConfig.AuditLogConfigs, logConfig)
+				}
+				logConfig.ExemptedMembers = appendUnique(logConfig.ExemptedMembers, l.ExemptedMembers...)
+			}
+		}
+	}
+
+	return NewIamPolicyResource(merged)
+}
+
 // Marshal returns the underlying resource's JSON representation
 func (r *IamPolicyResource) Marshal() ([]byte, error) {
 	return json.Marshal(&r.p)
 }
 
-// PolicyViolatesUserDomainWhitelist returns whether the policy contains a user or domain that is not part of the domain whitelist
-func (r *IamPolicyResource) PolicyViolatesUserDomainWhitelist() (err error) {
+// HasMember returns whether the policy grants any role to the given member
+func (r *IamPolicyResource) HasMember(member string) bool {
+	for _, b := range r.p.Bindings {
+		for _, m := range b.Members {
+			if m == member {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PolicyAllowsPublicAccess returns an error when the policy grants a role to allUsers or allAuthenticatedUsers
+func (r *IamPolicyResource) PolicyAllowsPublicAccess() (err error) {
+
+	var errBuilder strings.Builder
+
+	for _, b := range r.p.Bindings {
+		for _, member := range b.Members {
+			for _, public := range publicMembers {
+				if member == public {
+					errBuilder.WriteString(fmt.Sprintf("%v has role %v. ", member, b.Role))
+				}
+			}
+		}
+	}
+
+	errString := errBuilder.String()
+	if errString != "" {
+		err = errors.New(errString)
+	}
+
+	return
+}
+
+// PolicyViolatesUserDomainWhitelist returns whether the policy contains a user or domain that is not part of the given domain whitelist
+func (r *IamPolicyResource) PolicyViolatesUserDomainWhitelist(userDomains []string) (err error) {
 
 	var errBuilder strings.Builder
 	for _, b := range r.p.Bindings {
@@ -149,8 +356,8 @@ func (r *IamPolicyResource) findMembersWithOverlappingRoles(roleA string, roleB
 				if bb.Role == roleB {
 					aMembers = b.Members
 					bMembers = bb.Members
+					break
 				}
-				break
 			}
 			break
 		}
@@ -237,6 +444,11 @@ func (r *IamPolicyResource) PolicyViolatesKMSSeparationoOfDuties() (err error) {
 	return
 }
 
+// HasAuditConfigs returns whether the IAM policy defines any Cloud Audit logging configuration
+func (r *IamPolicyResource) HasAuditConfigs() bool {
+	return len(r.p.AuditConfigs) > 0
+}
+
 // PolicyConfiguresAuditLogging returns whether the IAM policy defines Cloud Audit logging
 func (r *IamPolicyResource) PolicyConfiguresAuditLogging() error {
 
@@ -1 +1,97 @@
 package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
+)
+
+func TestIamPolicyResourceServiceAccountSeparationOfDuties(t *testing.T) {
+
+	// The overlapping role is not the first binding of the policy
+	policy := NewIamPolicyResource(&cloudresourcemanager.Policy{
+		Bindings: []*cloudresourcemanager.Binding{
+			{Role: "roles/viewer", Members: []string{"user:alice@example.com"}},
+			{Role: serviceAccountAdminRole, Members: []string{"user:alice@example.com"}},
+			{Role: serviceAccountUserRole, Members: []string{"user:alice@example.com"}},
+		},
+	})
+	assert.NotNil(t, policy.PolicyViolatesServiceAccountSeparationoOfDuties())
+}
+
+func TestMergeIamPolicyResources(t *testing.T) {
+
+	a := NewIamPolicyResource(&cloudresourcemanager.Policy{
+		Bindings: []*cloudresourcemanager.Binding{
+			{Role: "roles/viewer", Members: []string{"user:alice@example.com"}},
+		},
+	})
+	b := NewIamPolicyResource(&cloudresourcemanager.Policy{
+		Bindings: []*cloudresourcemanager.Binding{
+			{Role: "roles/viewer", Members: []string{"user:alice@example.com", "allUsers"}},
+			{Role: "roles/editor", Members: []string{"user:bob@example.com"}},
+		},
+	})
+
+	merged := MergeIamPolicyResources(a, nil, b)
+	assert.Len(t, merged.p.Bindings, 2)
+	assert.Equal(t, []string{"user:alice@example.com", "allUsers"}, merged.p.Bindings[0].Members)
+	assert.NotNil(t, merged.PolicyAllowsPublicAccess())
+	assert.Nil(t, a.PolicyAllowsPublicAccess())
+}
+
+func TestMergeIamPolicyResourcesConditions(t *testing.T) {
+
+	expiring := &cloudresourcemanager.Expr{Title: "expiring", Expression: `request.time < timestamp("2020-01-01T00:00:00Z")`}
+	folder := NewIamPolicyResourceFromFolder(&cloudresourcemanagerv2.Policy{
+		Bindings: []*cloudresourcemanagerv2.Binding{
+			{Role: "roles/owner", Members: []string{"user:alice@example.com"}, Condition: &cloudresourcemanagerv2.Expr{Title: "expiring", Expression: expiring.Expression}},
+		},
+	})
+	project := NewIamPolicyResource(&cloudresourcemanager.Policy{
+		Bindings: []*cloudresourcemanager.Binding{
+			{Role: "roles/owner", Members: []string{"user:bob@example.com"}},
+			{Role: "roles/owner", Members: []string{"user:carol@example.com"}, Condition: expiring},
+		},
+	})
+
+	// Conditional bindings are kept apart from unconditional bindings of the same role
+	merged := MergeIamPolicyResources(folder, project)
+	assert.Len(t, merged.p.Bindings, 2)
+	assert.Equal(t, []string{"user:alice@example.com", "user:carol@example.com"}, merged.p.Bindings[0].Members)
+	assert.Equal(t, expiring.Expression, merged.p.Bindings[0].Condition.Expression)
+	assert.Equal(t, []string{"user:bob@example.com"}, merged.p.Bindings[1].Members)
+	assert.Nil(t, merged.p.Bindings[1].Condition)
+}
+
+func TestMergeIamPolicyResourcesAuditConfigs(t *testing.T) {
+
+	// Audit logging configured at the organization level is inherited by projects
+	org := NewIamPolicyResourceFromFolder(&cloudresourcemanagerv2.Policy{
+		AuditConfigs: []*cloudresourcemanagerv2.AuditConfig{{
+			Service: "allServices",
+			AuditLogConfigs: []*cloudresourcemanagerv2.AuditLogConfig{
+				{LogType: "ADMIN_READ"},
+				{LogType: "DATA_READ"},
+			},
+		}},
+	})
+	project := NewIamPolicyResource(&cloudresourcemanager.Policy{
+		AuditConfigs: []*cloudresourcemanager.AuditConfig{{
+			Service: "allServices",
+			AuditLogConfigs: []*cloudresourcemanager.AuditLogConfig{
+				{LogType: "DATA_WRITE", ExemptedMembers: []string{"user:alice@example.com"}},
+			},
+		}},
+	})
+
+	assert.True(t, org.HasAuditConfigs())
+	assert.NotNil(t, org.PolicyConfiguresAuditLogging())
+	assert.NotNil(t, project.PolicyConfiguresAuditLogging())
+
+	merged := MergeIamPolicyResources(org, project)
+	assert.Nil(t, merged.PolicyConfiguresAuditLogging())
+	assert.NotNil(t, merged.PolicyDoesNotHaveAuditLogExceptions())
+}
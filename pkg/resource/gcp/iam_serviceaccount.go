@@ -15,6 +15,9 @@ import (
 type IamServiceAccountResource struct {
 	s    *iam.ServiceAccount
 	Keys []*iam.ServiceAccountKey
+
+	// The error that prevented the service account's keys from being collected
+	KeysError error
 }
 
 // NewIamServiceAccountResource returns a new IamServiceAccountResource
@@ -40,8 +43,8 @@ func (r *IamServiceAccountResource) HasUserManagedKeys() bool {
 	return len(r.Keys) != 0
 }
 
-// HasKeysNeedingRotation returns an error when the service account has keys older than the allowed time
-func (r *IamServiceAccountResource) HasKeysNeedingRotation() (err error) {
+// HasKeysNeedingRotation returns an error when the service account has keys older than the given number of days
+func (r *IamServiceAccountResource) HasKeysNeedingRotation(saKeyExpirationTime int) (err error) {
 
 	var errBuilder strings.Builder
 
@@ -51,7 +54,7 @@ func (r *IamServiceAccountResource) HasKeysNeedingRotation() (err error) {
 			glog.Fatalf("Failed to parse timestamp when checking keys: %v", err)
 		}
 
-		if t.Sub(time.Now()).Hours() > float64(saKeyExpirationTime*24) {
+		if time.Since(t).Hours() > float64(saKeyExpirationTime*24) {
 			errBuilder.WriteString(fmt.Sprintf("%v has key older than %v days. ", k.Name, saKeyExpirationTime))
 		}
 
@@ -1 +1,23 @@
 package gcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	iam "google.golang.org/api/iam/v1"
+)
+
+func TestIamServiceAccountResourceHasKeysNeedingRotation(t *testing.T) {
+
+	sa := NewIamServiceAccountResource(&iam.ServiceAccount{Email: "sa@my-project.iam.gserviceaccount.com"})
+	assert.Nil(t, sa.HasKeysNeedingRotation(90))
+
+	// A key created 30 days ago is within the expiration time
+	sa.Keys = append(sa.Keys, &iam.ServiceAccountKey{Name: "recent", ValidAfterTime: time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339)})
+	assert.Nil(t, sa.HasKeysNeedingRotation(90))
+
+	// A key created 120 days ago needs rotation
+	sa.Keys = append(sa.Keys, &iam.ServiceAccountKey{Name: "old", ValidAfterTime: time.Now().Add(-120 * 24 * time.Hour).Format(time.RFC3339)})
+	assert.NotNil(t, sa.HasKeysNeedingRotation(90))
+}
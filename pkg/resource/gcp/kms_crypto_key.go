@@ -0,0 +1,106 @@
+package gcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+const (
+	// Only symmetric keys support automatic rotation
+	kmsSymmetricPurpose = "ENCRYPT_DECRYPT"
+)
+
+// KMSCryptoKeyResource represents a Google Cloud KMS crypto key
+type KMSCryptoKeyResource struct {
+	k       *cloudkms.CryptoKey
+	keyRing *KMSKeyRingResource
+	Policy  *IamPolicyResource
+}
+
+// NewKMSCryptoKeyResource returns a new KMSCryptoKeyResource that belongs to the given key ring
+func NewKMSCryptoKeyResource(k *cloudkms.CryptoKey, keyRing *KMSKeyRingResource) *KMSCryptoKeyResource {
+	r := new(KMSCryptoKeyResource)
+	r.k = k
+	r.keyRing = keyRing
+	r.Policy = NewIamPolicyResourceFromKMS(nil)
+	return r
+}
+
+// Name returns the short name of the crypto key
+func (r *KMSCryptoKeyResource) Name() string {
+	return r.k.Name[strings.LastIndex(r.k.Name, "/")+1:]
+}
+
+// KeyRing returns the key ring the crypto key belongs to
+func (r *KMSCryptoKeyResource) KeyRing() *KMSKeyRingResource {
+	return r.keyRing
+}
+
+// ResourceName returns the full resource name of the crypto key
+func (r *KMSCryptoKeyResource) ResourceName() string {
+	return "//cloudkms.googleapis.com/" + r.k.Name
+}
+
+// Labels returns the labels of the crypto key
+func (r *KMSCryptoKeyResource) Labels() map[string]string {
+	return r.k.Labels
+}
+
+// Marshal returns the underlying resource's JSON representation
+func (r *KMSCryptoKeyResource) Marshal() ([]byte, error) {
+	return json.Marshal(&r.k)
+}
+
+// IsSymmetric returns whether the crypto key is used for symmetric encryption and decryption
+func (r *KMSCryptoKeyResource) IsSymmetric() bool {
+	return r.k.Purpose == kmsSymmetricPurpose
+}
+
+// EffectivePolicy returns the key's IAM policy combined with the policy inherited from its key ring
+func (r *KMSCryptoKeyResource) EffectivePolicy() *IamPolicyResource {
+	if r.keyRing == nil {
+		return r.Policy
+	}
+	return MergeIamPolicyResources(r.keyRing.Policy, r.Policy)
+}
+
+// IsRotatedWithinPeriod returns an error when the crypto key is not automatically rotated within the given number of days
+func (r *KMSCryptoKeyResource) IsRotatedWithinPeriod(kmsKeyRotationTime int) (err error) {
+
+	maxPeriod := time.Duration(kmsKeyRotationTime) * 24 * time.Hour
+
+	if r.k.RotationPeriod == "" {
+		return errors.New("Key does not have a rotation period configured")
+	}
+
+	// Rotation periods are formatted as a duration in seconds, e.g. "7776000s"
+	seconds, err := strconv.ParseFloat(strings.TrimSuffix(r.k.RotationPeriod, "s"), 64)
+	if err != nil {
+		return fmt.Errorf("Failed to parse rotation period %v: %v", r.k.RotationPeriod, err)
+	}
+
+	if period := time.Duration(seconds) * time.Second; period > maxPeriod {
+		return fmt.Errorf("Key rotation period of %v days is longer than %v days", int(period.Hours()/24), kmsKeyRotationTime)
+	}
+
+	if r.k.NextRotationTime == "" {
+		return errors.New("Key does not have a next rotation time scheduled")
+	}
+
+	next, err := time.Parse(time.RFC3339, r.k.NextRotationTime)
+	if err != nil {
+		return fmt.Errorf("Failed to parse next rotation time %v: %v", r.k.NextRotationTime, err)
+	}
+
+	if time.Until(next) > maxPeriod {
+		return fmt.Errorf("Key is next rotated on %v, which is more than %v days away", r.k.NextRotationTime, kmsKeyRotationTime)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package gcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+func TestKMSCryptoKeyResourceIsRotatedWithinPeriod(t *testing.T) {
+
+	keyRing := NewKMSKeyRingResource(&cloudkms.KeyRing{Name: "projects/my-project/locations/us-east1/keyRings/my-ring"})
+
+	// A key that is rotated every 90 days passes
+	key := NewKMSCryptoKeyResource(&cloudkms.CryptoKey{
+		Name:             "projects/my-project/locations/us-east1/keyRings/my-ring/cryptoKeys/my-key",
+		Purpose:          "ENCRYPT_DECRYPT",
+		RotationPeriod:   "7776000s",
+		NextRotationTime: time.Now().Add(30 * 24 * time.Hour).Format(time.RFC3339),
+	}, keyRing)
+	assert.Equal(t, "my-key", key.Name())
+	assert.Equal(t, "my-ring", key.KeyRing().Name())
+	assert.Equal(t, "us-east1", key.KeyRing().Location())
+	assert.True(t, key.IsSymmetric())
+	assert.Nil(t, key.IsRotatedWithinPeriod(365))
+
+	// A key without rotation configured fails
+	key = NewKMSCryptoKeyResource(&cloudkms.CryptoKey{Purpose: "ENCRYPT_DECRYPT"}, keyRing)
+	assert.NotNil(t, key.IsRotatedWithinPeriod(365))
+
+	// A key rotated every two years fails
+	key = NewKMSCryptoKeyResource(&cloudkms.CryptoKey{
+		Purpose:          "ENCRYPT_DECRYPT",
+		RotationPeriod:   "63072000s",
+		NextRotationTime: time.Now().Add(30 * 24 * time.Hour).Format(time.RFC3339),
+	}, keyRing)
+	assert.NotNil(t, key.IsRotatedWithinPeriod(365))
+}
+
+func TestKMSCryptoKeyResourceEffectivePolicy(t *testing.T) {
+
+	// The key ring grants admin, while the key grants usage to the same member
+	keyRing := NewKMSKeyRingResource(&cloudkms.KeyRing{Name: "projects/my-project/locations/global/keyRings/my-ring"})
+	keyRing.Policy = NewIamPolicyResourceFromKMS(&cloudkms.Policy{
+		Bindings: []*cloudkms.Binding{
+			{Role: "roles/cloudkms.admin", Members: []string{"user:alice@example.com"}},
+		},
+	})
+	key := NewKMSCryptoKeyResource(&cloudkms.CryptoKey{Name: "my-key"}, keyRing)
+	key.Policy = NewIamPolicyResourceFromKMS(&cloudkms.Policy{
+		Bindings: []*cloudkms.Binding{
+			{Role: "roles/cloudkms.cryptoKeyEncrypterDecrypter", Members: []string{"user:alice@example.com"}},
+		},
+	})
+
+	// Neither policy violates separation of duties on its own
+	assert.Nil(t, keyRing.Policy.PolicyViolatesKMSSeparationoOfDuties())
+	assert.Nil(t, key.Policy.PolicyViolatesKMSSeparationoOfDuties())
+
+	// But the effective policy does
+	assert.NotNil(t, key.EffectivePolicy().PolicyViolatesKMSSeparationoOfDuties())
+	assert.Nil(t, key.EffectivePolicy().PolicyAllowsPublicAccess())
+}
@@ -0,0 +1,47 @@
+package gcp
+
+import (
+	"encoding/json"
+	"strings"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// KMSKeyRingResource represents a Google Cloud KMS key ring
+type KMSKeyRingResource struct {
+	k      *cloudkms.KeyRing
+	Policy *IamPolicyResource
+}
+
+// NewKMSKeyRingResource returns a new KMSKeyRingResource
+func NewKMSKeyRingResource(k *cloudkms.KeyRing) *KMSKeyRingResource {
+	r := new(KMSKeyRingResource)
+	r.k = k
+	r.Policy = NewIamPolicyResourceFromKMS(nil)
+	return r
+}
+
+// Name returns the short name of the key ring
+func (r *KMSKeyRingResource) Name() string {
+	return r.k.Name[strings.LastIndex(r.k.Name, "/")+1:]
+}
+
+// FullName returns the fully qualified resource name of the key ring
+func (r *KMSKeyRingResource) FullName() string {
+	return r.k.Name
+}
+
+// Location returns the location the key ring resides in
+func (r *KMSKeyRingResource) Location() string {
+	// Key ring names are formatted as projects/<project>/locations/<location>/keyRings/<name>
+	parts := strings.Split(r.k.Name, "/")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
+
+// Marshal returns the underlying resource's JSON representation
+func (r *KMSKeyRingResource) Marshal() ([]byte, error) {
+	return json.Marshal(&r.k)
+}
@@ -1,9 +1,68 @@
 package gcp
 
 import (
+	"fmt"
+	"strings"
+
 	loggingpb "google.golang.org/genproto/googleapis/logging/v2"
 )
 
+const (
+	// LoggingSinkDestinationStorage is a sink destination that exports to a GCS bucket
+	LoggingSinkDestinationStorage = "storage"
+
+	// LoggingSinkDestinationBigQuery is a sink destination that exports to a BigQuery dataset
+	LoggingSinkDestinationBigQuery = "bigquery"
+
+	// LoggingSinkDestinationPubSub is a sink destination that exports to a Pub/Sub topic
+	LoggingSinkDestinationPubSub = "pubsub"
+
+	// LoggingSinkDestinationLogging is a sink destination that exports to a logging bucket
+	LoggingSinkDestinationLogging = "logging"
+)
+
+// LoggingSinkDestination is the parsed destination of a logging sink
+type LoggingSinkDestination struct {
+	// The type of destination, one of the LoggingSinkDestination* constants
+	Type string
+
+	// The project the destination resides in. Empty for GCS buckets, as
+	// bucket names are globally unique
+	Project string
+
+	// The location of the destination. Only set for logging buckets
+	Location string
+
+	// The name of the bucket, dataset or topic
+	Name string
+}
+
+// ParseLoggingSinkDestination parses a sink destination string into a typed destination
+func ParseLoggingSinkDestination(destination string) (*LoggingSinkDestination, error) {
+
+	parts := strings.Split(destination, "/")
+
+	switch {
+	// storage.googleapis.com/[BUCKET]
+	case parts[0] == "storage.googleapis.com" && len(parts) == 2:
+		return &LoggingSinkDestination{Type: LoggingSinkDestinationStorage, Name: parts[1]}, nil
+
+	// bigquery.googleapis.com/projects/[PROJECT]/datasets/[DATASET]
+	case parts[0] == "bigquery.googleapis.com" && len(parts) == 5 && parts[1] == "projects" && parts[3] == "datasets":
+		return &LoggingSinkDestination{Type: LoggingSinkDestinationBigQuery, Project: parts[2], Name: parts[4]}, nil
+
+	// pubsub.googleapis.com/projects/[PROJECT]/topics/[TOPIC]
+	case parts[0] == "pubsub.googleapis.com" && len(parts) == 5 && parts[1] == "projects" && parts[3] == "topics":
+		return &LoggingSinkDestination{Type: LoggingSinkDestinationPubSub, Project: parts[2], Name: parts[4]}, nil
+
+	// logging.googleapis.com/projects/[PROJECT]/locations/[LOCATION]/buckets/[BUCKET]
+	case parts[0] == "logging.googleapis.com" && len(parts) == 7 && parts[1] == "projects" && parts[3] == "locations" && parts[5] == "buckets":
+		return &LoggingSinkDestination{Type: LoggingSinkDestinationLogging, Project: parts[2], Location: parts[4], Name: parts[6]}, nil
+	}
+
+	return nil, fmt.Errorf("Unrecognized logging sink destination: %v", destination)
+}
+
 // LoggingSinkResource represents a StackDriver logging sink
 type LoggingSinkResource struct {
 	s *loggingpb.LogSink
@@ -16,6 +75,11 @@ func NewLoggingSinkResource(sink *loggingpb.LogSink) *LoggingSinkResource {
 	return r
 }
 
+// Name returns the name of the logging sink
+func (r *LoggingSinkResource) Name() string {
+	return r.s.Name
+}
+
 // ShipsAllLogs indicates whether there is no filter (and thus all logs are shipped)
 func (r *LoggingSinkResource) ShipsAllLogs() bool {
 
@@ -23,3 +87,8 @@ func (r *LoggingSinkResource) ShipsAllLogs() bool {
 	// that are generated are shipped to the logging sink destination
 	return r.s.Filter == ""
 }
+
+// Destination returns the parsed destination the logging sink exports to
+func (r *LoggingSinkResource) Destination() (*LoggingSinkDestination, error) {
+	return ParseLoggingSinkDestination(r.s.Destination)
+}
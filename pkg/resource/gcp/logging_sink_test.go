@@ -1 +1,42 @@
 package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLoggingSinkDestination(t *testing.T) {
+
+	tests := []struct {
+		destination string
+		expected    LoggingSinkDestination
+	}{
+		{
+			destination: "storage.googleapis.com/my-log-bucket",
+			expected:    LoggingSinkDestination{Type: LoggingSinkDestinationStorage, Name: "my-log-bucket"},
+		},
+		{
+			destination: "bigquery.googleapis.com/projects/my-project/datasets/my_dataset",
+			expected:    LoggingSinkDestination{Type: LoggingSinkDestinationBigQuery, Project: "my-project", Name: "my_dataset"},
+		},
+		{
+			destination: "pubsub.googleapis.com/projects/my-project/topics/my-topic",
+			expected:    LoggingSinkDestination{Type: LoggingSinkDestinationPubSub, Project: "my-project", Name: "my-topic"},
+		},
+		{
+			destination: "logging.googleapis.com/projects/my-project/locations/global/buckets/_Default",
+			expected:    LoggingSinkDestination{Type: LoggingSinkDestinationLogging, Project: "my-project", Location: "global", Name: "_Default"},
+		},
+	}
+
+	for _, test := range tests {
+		dest, err := ParseLoggingSinkDestination(test.destination)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, *dest)
+	}
+
+	// Unknown destinations should return an error
+	_, err := ParseLoggingSinkDestination("example.com/my-destination")
+	assert.NotNil(t, err)
+}
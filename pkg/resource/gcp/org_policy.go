@@ -0,0 +1,73 @@
+package gcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+const (
+	orgPolicyConstraintPrefix = "constraints/"
+	orgPolicyAllValuesDeny    = "DENY"
+)
+
+// OrgPolicyConstraintName returns the full name of an organization policy constraint, e.g. constraints/compute.requireOsLogin
+func OrgPolicyConstraintName(constraint string) string {
+	if strings.HasPrefix(constraint, orgPolicyConstraintPrefix) {
+		return constraint
+	}
+	return orgPolicyConstraintPrefix + constraint
+}
+
+// OrgPolicyResource represents the effective organization policy of a constraint for a project
+type OrgPolicyResource struct {
+	p *cloudresourcemanager.OrgPolicy
+}
+
+// NewOrgPolicyResource returns a new OrgPolicyResource
+func NewOrgPolicyResource(p *cloudresourcemanager.OrgPolicy) *OrgPolicyResource {
+	r := new(OrgPolicyResource)
+	r.p = p
+	return r
+}
+
+// Marshal returns the underlying resource's JSON representation
+func (r *OrgPolicyResource) Marshal() ([]byte, error) {
+	return json.Marshal(&r.p)
+}
+
+// Constraint returns the full name of the policy's constraint
+func (r *OrgPolicyResource) Constraint() string {
+	return OrgPolicyConstraintName(r.p.Constraint)
+}
+
+// IsEnforced returns whether the constraint is enforced. Boolean constraints must be enforced,
+// and list constraints must deny all values
+func (r *OrgPolicyResource) IsEnforced() error {
+
+	if r.p.RestoreDefault != nil {
+		return errors.New("Organization policy is restored to the constraint's default behavior")
+	}
+
+	if r.p.BooleanPolicy != nil {
+		if !r.p.BooleanPolicy.Enforced {
+			return errors.New("Organization policy is not enforced")
+		}
+		return nil
+	}
+
+	if r.p.ListPolicy != nil {
+		if r.p.ListPolicy.AllValues == orgPolicyAllValuesDeny {
+			return nil
+		}
+		if len(r.p.ListPolicy.AllowedValues) > 0 {
+			return fmt.Errorf("Organization policy allows values: %v", strings.Join(r.p.ListPolicy.AllowedValues, ", "))
+		}
+		return errors.New("Organization policy does not deny all values")
+	}
+
+	return errors.New("Organization policy is not set")
+}
@@ -0,0 +1,34 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestOrgPolicyConstraintName(t *testing.T) {
+	assert.Equal(t, "constraints/compute.requireOsLogin", OrgPolicyConstraintName("compute.requireOsLogin"))
+	assert.Equal(t, "constraints/compute.requireOsLogin", OrgPolicyConstraintName("constraints/compute.requireOsLogin"))
+}
+
+func TestOrgPolicyResourceIsEnforced(t *testing.T) {
+
+	tests := []struct {
+		policy   *cloudresourcemanager.OrgPolicy
+		enforced bool
+	}{
+		{&cloudresourcemanager.OrgPolicy{BooleanPolicy: &cloudresourcemanager.BooleanPolicy{Enforced: true}}, true},
+		{&cloudresourcemanager.OrgPolicy{BooleanPolicy: &cloudresourcemanager.BooleanPolicy{}}, false},
+		{&cloudresourcemanager.OrgPolicy{ListPolicy: &cloudresourcemanager.ListPolicy{AllValues: "DENY"}}, true},
+		{&cloudresourcemanager.OrgPolicy{ListPolicy: &cloudresourcemanager.ListPolicy{AllValues: "ALLOW"}}, false},
+		{&cloudresourcemanager.OrgPolicy{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"projects/p/zones/z/instances/i"}}}, false},
+		{&cloudresourcemanager.OrgPolicy{RestoreDefault: &cloudresourcemanager.RestoreDefault{}}, false},
+		{&cloudresourcemanager.OrgPolicy{}, false},
+	}
+
+	for _, test := range tests {
+		err := NewOrgPolicyResource(test.policy).IsEnforced()
+		assert.Equal(t, test.enforced, err == nil, "%+v", test.policy)
+	}
+}
@@ -0,0 +1,30 @@
+package gcp
+
+import (
+	"strings"
+)
+
+const (
+	// GlobalLocation is the location of resources that do not reside in a region or zone
+	GlobalLocation = "global"
+)
+
+// selfLinkResourceName converts an API self link, such as https://www.googleapis.com/compute/v1/projects/p/zones/z/instances/i,
+// into the full resource name of the given service, such as //compute.googleapis.com/projects/p/zones/z/instances/i
+func selfLinkResourceName(service string, selfLink string) string {
+	i := strings.Index(selfLink, "projects/")
+	if i < 0 {
+		return ""
+	}
+	return "//" + service + "/" + selfLink[i:]
+}
+
+// lastSegment returns the part of a URL after its last '/', such as the zone of a compute instance
+func lastSegment(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+// ProjectResourceName returns the full resource name of a project
+func ProjectResourceName(projectID string) string {
+	return "//cloudresourcemanager.googleapis.com/projects/" + projectID
+}
@@ -0,0 +1,52 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	compute "google.golang.org/api/compute/v1"
+	container "google.golang.org/api/container/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+	storage "google.golang.org/api/storage/v1"
+)
+
+func TestResourceNames(t *testing.T) {
+
+	instance := NewComputeInstanceResource(&compute.Instance{
+		Name:     "my-instance",
+		Zone:     "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a",
+		SelfLink: "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance",
+	})
+	assert.Equal(t, "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/my-instance", instance.ResourceName())
+	assert.Equal(t, "us-central1-a", instance.Location())
+
+	subnetwork := NewComputeSubnetworkResource(&compute.Subnetwork{
+		Region:   "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1",
+		SelfLink: "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1/subnetworks/default",
+	})
+	assert.Equal(t, "//compute.googleapis.com/projects/my-project/regions/europe-west1/subnetworks/default", subnetwork.ResourceName())
+	assert.Equal(t, "europe-west1", subnetwork.Location())
+
+	// Global addresses have no region
+	address := NewComputeAddressResource(&compute.Address{Name: "my-address"})
+	assert.Equal(t, "", address.ResourceName())
+	assert.Equal(t, GlobalLocation, address.Location())
+
+	bucket := NewStorageBucketResource(&storage.Bucket{Name: "my-bucket", Location: "EU"})
+	assert.Equal(t, "//storage.googleapis.com/projects/_/buckets/my-bucket", bucket.ResourceName())
+	assert.Equal(t, "eu", bucket.Location())
+
+	cluster := NewContainerClusterResource(&container.Cluster{
+		SelfLink: "https://container.googleapis.com/v1/projects/my-project/zones/us-central1-a/clusters/my-cluster",
+	})
+	assert.Equal(t, "//container.googleapis.com/projects/my-project/locations/us-central1-a/clusters/my-cluster", cluster.ResourceName())
+
+	sql := NewSqlInstanceResource(&sqladmin.DatabaseInstance{Project: "my-project", Name: "my-db"})
+	assert.Equal(t, "//cloudsql.googleapis.com/projects/my-project/instances/my-db", sql.ResourceName())
+
+	key := NewKMSCryptoKeyResource(&cloudkms.CryptoKey{Name: "projects/my-project/locations/global/keyRings/ring/cryptoKeys/key"}, nil)
+	assert.Equal(t, "//cloudkms.googleapis.com/projects/my-project/locations/global/keyRings/ring/cryptoKeys/key", key.ResourceName())
+
+	assert.Equal(t, "//cloudresourcemanager.googleapis.com/projects/my-project", ProjectResourceName("my-project"))
+}
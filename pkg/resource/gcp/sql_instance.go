@@ -0,0 +1,157 @@
+package gcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+)
+
+const (
+	// Host values that allow a Cloud SQL user to connect from anywhere
+	sqlAnyHost = "%"
+
+	// The default administrative user for MySQL instances
+	sqlRootUser = "root"
+)
+
+// SqlInstanceResource represents a Google Cloud SQL database instance
+type SqlInstanceResource struct {
+	i     *sqladmin.DatabaseInstance
+	Users []*sqladmin.User
+}
+
+// NewSqlInstanceResource returns a new SqlInstanceResource
+func NewSqlInstanceResource(i *sqladmin.DatabaseInstance) *SqlInstanceResource {
+	r := new(SqlInstanceResource)
+	r.i = i
+	r.Users = []*sqladmin.User{}
+	return r
+}
+
+// Name returns the name of the Cloud SQL instance
+func (r *SqlInstanceResource) Name() string {
+	return r.i.Name
+}
+
+// Region returns the GCP region of the Cloud SQL instance
+func (r *SqlInstanceResource) Region() string {
+	return r.i.Region
+}
+
+// ResourceName returns the full resource name of the Cloud SQL instance
+func (r *SqlInstanceResource) ResourceName() string {
+	return fmt.Sprintf("//cloudsql.googleapis.com/projects/%v/instances/%v", r.i.Project, r.i.Name)
+}
+
+// Labels returns the user labels of the Cloud SQL instance
+func (r *SqlInstanceResource) Labels() map[string]string {
+	if r.i.Settings == nil {
+		return nil
+	}
+	return r.i.Settings.UserLabels
+}
+
+// Marshal returns the underlying resource's JSON representation
+func (r *SqlInstanceResource) Marshal() ([]byte, error) {
+	return json.Marshal(&r.i)
+}
+
+// IsMySQL returns whether the Cloud SQL instance is running a MySQL database
+func (r *SqlInstanceResource) IsMySQL() bool {
+	return strings.HasPrefix(r.i.DatabaseVersion, "MYSQL")
+}
+
+// IsRunnable returns whether the Cloud SQL instance is in a state that can serve requests
+func (r *SqlInstanceResource) IsRunnable() bool {
+	return r.i.State == "RUNNABLE"
+}
+
+func (r *SqlInstanceResource) ipConfiguration() *sqladmin.IpConfiguration {
+	if r.i.Settings == nil {
+		return nil
+	}
+	return r.i.Settings.IpConfiguration
+}
+
+// RequiresSSL returns whether the Cloud SQL instance requires all incoming connections to use SSL
+func (r *SqlInstanceResource) RequiresSSL() bool {
+	ipConfig := r.ipConfiguration()
+	if ipConfig == nil {
+		return false
+	}
+	return ipConfig.RequireSsl
+}
+
+// HasPublicIP returns whether the Cloud SQL instance has a public IPv4 address assigned
+func (r *SqlInstanceResource) HasPublicIP() bool {
+	ipConfig := r.ipConfiguration()
+	if ipConfig == nil {
+		return false
+	}
+	return ipConfig.Ipv4Enabled
+}
+
+// IsOpenToTheWorld returns an error when the Cloud SQL instance authorizes connections from a network broad enough
+// to be considered the internet, such as 0.0.0.0/0 or 128.0.0.0/1
+func (r *SqlInstanceResource) IsOpenToTheWorld() (err error) {
+
+	ipConfig := r.ipConfiguration()
+	if ipConfig == nil || !ipConfig.Ipv4Enabled {
+		return
+	}
+
+	var errBuilder strings.Builder
+
+	for _, n := range ipConfig.AuthorizedNetworks {
+		network, err := parseCIDR(n.Value)
+		if err != nil {
+			continue
+		}
+		if isInternetRange(network) {
+			errBuilder.WriteString(fmt.Sprintf("Authorized network %v allows connections from %v. ", n.Name, n.Value))
+		}
+	}
+
+	errString := errBuilder.String()
+	if errString != "" {
+		err = errors.New(errString)
+	}
+
+	return
+}
+
+// AllowsUsersFromAnyHost returns an error when the Cloud SQL instance has users that can connect from any host.
+// Users created through the Cloud SQL API are granted administrative privileges on the instance
+func (r *SqlInstanceResource) AllowsUsersFromAnyHost() (err error) {
+
+	var errBuilder strings.Builder
+
+	for _, u := range r.Users {
+		if u.Host == sqlAnyHost || u.Host == "" {
+			errBuilder.WriteString(fmt.Sprintf("%v can connect from any host. ", u.Name))
+		}
+	}
+
+	errString := errBuilder.String()
+	if errString != "" {
+		err = errors.New(errString)
+	}
+
+	return
+}
+
+// AllowsRootFromAnyHost returns an error when the root user of the Cloud SQL instance can connect from any host
+func (r *SqlInstanceResource) AllowsRootFromAnyHost() (err error) {
+
+	for _, u := range r.Users {
+		if u.Name == sqlRootUser && (u.Host == sqlAnyHost || u.Host == "") {
+			err = fmt.Errorf("%v can connect from any host", u.Name)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,98 @@
+package gcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+)
+
+// Helper function for making fake Cloud SQL instance resources
+func makeTestSqlInstance(data []byte) *sqladmin.DatabaseInstance {
+	instance := new(sqladmin.DatabaseInstance)
+	_ = json.Unmarshal(data, &instance)
+	return instance
+}
+
+var (
+	testOpenSqlInstanceData = []byte(`
+{
+	"name": "my-test-instance",
+	"databaseVersion": "MYSQL_5_7",
+	"region": "us-central1",
+	"state": "RUNNABLE",
+	"settings": {
+		"ipConfiguration": {
+			"ipv4Enabled": true,
+			"requireSsl": false,
+			"authorizedNetworks": [
+				{
+					"name": "office",
+					"value": "203.0.113.0/24"
+				},
+				{
+					"name": "everyone",
+					"value": "0.0.0.0/0"
+				}
+			]
+		}
+	}
+}
+`)
+
+	testOpenSqlInstance = makeTestSqlInstance(testOpenSqlInstanceData)
+)
+
+func TestSqlInstanceResourceNetworking(t *testing.T) {
+
+	instanceResource := NewSqlInstanceResource(testOpenSqlInstance)
+	assert.Equal(t, "my-test-instance", instanceResource.Name())
+	assert.True(t, instanceResource.IsMySQL())
+	assert.False(t, instanceResource.RequiresSSL())
+	assert.NotNil(t, instanceResource.IsOpenToTheWorld())
+
+	// Broad public ranges are open to the world, while narrow or private ranges are not
+	for network, open := range map[string]bool{
+		"0.0.0.0/1":      true,
+		"128.0.0.0/1":    true,
+		"::/0":           true,
+		"203.0.113.0/24": false,
+		"10.0.0.0/8":     false,
+	} {
+		instance := makeTestSqlInstance([]byte(`{"name": "db", "settings": {"ipConfiguration": {"ipv4Enabled": true, "authorizedNetworks": [{"name": "net", "value": "` + network + `"}]}}}`))
+		assert.Equal(t, open, NewSqlInstanceResource(instance).IsOpenToTheWorld() != nil, network)
+	}
+}
+
+func TestSqlInstanceResourceUsers(t *testing.T) {
+
+	instanceResource := NewSqlInstanceResource(testOpenSqlInstance)
+
+	// Users restricted to a host should pass
+	instanceResource.Users = []*sqladmin.User{
+		{Name: "root", Host: "10.0.0.1"},
+		{Name: "app", Host: "10.0.0.2"},
+	}
+	assert.Nil(t, instanceResource.AllowsUsersFromAnyHost())
+	assert.Nil(t, instanceResource.AllowsRootFromAnyHost())
+
+	// A non-root user from any host fails only the admin check
+	instanceResource.Users = append(instanceResource.Users, &sqladmin.User{Name: "admin", Host: "%"})
+	assert.NotNil(t, instanceResource.AllowsUsersFromAnyHost())
+	assert.Nil(t, instanceResource.AllowsRootFromAnyHost())
+
+	// Root from any host fails both checks
+	instanceResource.Users = append(instanceResource.Users, &sqladmin.User{Name: "root", Host: "%"})
+	assert.NotNil(t, instanceResource.AllowsRootFromAnyHost())
+}
+
+func TestSqlInstanceResourceLabels(t *testing.T) {
+
+	i := NewSqlInstanceResource(makeTestSqlInstance([]byte(`{"name": "db", "settings": {"userLabels": {"team": "data"}}}`)))
+	assert.Equal(t, map[string]string{"team": "data"}, i.Labels())
+
+	// Instances without settings have no labels
+	i = NewSqlInstanceResource(makeTestSqlInstance([]byte(`{"name": "db"}`)))
+	assert.Nil(t, i.Labels())
+}
@@ -2,7 +2,9 @@ package gcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	storage "google.golang.org/api/storage/v1"
 )
@@ -10,12 +12,25 @@ import (
 // StorageBucketResource represents a Google Storage bucket resource
 type StorageBucketResource struct {
 	b *storage.Bucket
+
+	// The bucket's IAM policy
+	Policy *IamPolicyResource
+
+	// A sample of objects in the bucket, including their ACLs
+	SampledObjects []*storage.Object
+
+	// Errors that prevented the bucket's IAM policy, ACLs or object sample from being collected
+	PolicyError  error
+	ACLError     error
+	ObjectsError error
 }
 
 // NewStorageBucketResource returns a new StorageBucketResource
 func NewStorageBucketResource(b *storage.Bucket) *StorageBucketResource {
 	r := new(StorageBucketResource)
 	r.b = b
+	r.Policy = NewIamPolicyResourceFromStorage(nil)
+	r.SampledObjects = []*storage.Object{}
 	return r
 }
 
@@ -24,6 +39,21 @@ func (r *StorageBucketResource) Name() string {
 	return r.b.Name
 }
 
+// ResourceName returns the full resource name of the bucket
+func (r *StorageBucketResource) ResourceName() string {
+	return "//storage.googleapis.com/projects/_/buckets/" + r.b.Name
+}
+
+// Location returns the location of the bucket, such as us or europe-west1
+func (r *StorageBucketResource) Location() string {
+	return strings.ToLower(r.b.Location)
+}
+
+// Labels returns the labels of the bucket
+func (r *StorageBucketResource) Labels() map[string]string {
+	return r.b.Labels
+}
+
 // Marshal returns the underlying resource's JSON representation
 func (r *StorageBucketResource) Marshal() ([]byte, error) {
 	return json.Marshal(&r.b)
@@ -63,6 +93,56 @@ func (r *StorageBucketResource) AllowAllAuthenticatedUsers() (result bool) {
 	return
 }
 
+// PolicyAllowsMember checks whether the bucket's IAM policy grants any role to a given member
+func (r *StorageBucketResource) PolicyAllowsMember(member string) (result bool) {
+	return r.Policy.HasMember(member)
+}
+
+// IsLoggingEnabled checks whether access logs for the bucket are delivered to a log bucket
+func (r *StorageBucketResource) IsLoggingEnabled() bool {
+	return r.b.Logging != nil && r.b.Logging.LogBucket != ""
+}
+
+// HasPublicObjects returns an error when the bucket's default object ACL, or any of the
+// sampled objects' ACLs, grant access to allUsers or allAuthenticatedUsers
+func (r *StorageBucketResource) HasPublicObjects() (err error) {
+
+	var errBuilder strings.Builder
+
+	isPublic := func(entity string) bool {
+		return entity == "allUsers" || entity == "allAuthenticatedUsers"
+	}
+
+	for _, acl := range r.b.DefaultObjectAcl {
+		if isPublic(acl.Entity) {
+			errBuilder.WriteString(fmt.Sprintf("Default object ACL grants %v to %v. ", acl.Role, acl.Entity))
+		}
+	}
+
+	for _, o := range r.SampledObjects {
+		for _, acl := range o.Acl {
+			if isPublic(acl.Entity) {
+				errBuilder.WriteString(fmt.Sprintf("Object %v grants %v to %v. ", o.Name, acl.Role, acl.Entity))
+			}
+		}
+	}
+
+	errString := errBuilder.String()
+	if errString != "" {
+		err = errors.New(errString)
+	}
+
+	return
+}
+
+// AccessControlsError returns the error that prevented the bucket's IAM policy or ACLs from being collected, if any
+func (r *StorageBucketResource) AccessControlsError() error {
+	if r.PolicyError != nil {
+		return r.PolicyError
+	}
+	return r.ACLError
+}
+
 // HasBucketPolicyOnlyEnabled checks whether a bucket is configured to use permissions across the entire bucket
 func (r *StorageBucketResource) HasBucketPolicyOnlyEnabled() (result bool, err error) {
 
@@ -81,3 +161,19 @@ func (r *StorageBucketResource) HasBucketPolicyOnlyEnabled() (result bool, err e
 	}
 	return result, err
 }
+
+// IsVersioningEnabled checks whether object versioning is enabled for the bucket
+func (r *StorageBucketResource) IsVersioningEnabled() bool {
+	return r.b.Versioning != nil && r.b.Versioning.Enabled
+}
+
+// HasRetentionPolicy checks whether the bucket has a retention policy configured
+func (r *StorageBucketResource) HasRetentionPolicy() (err error) {
+	policy := r.b.RetentionPolicy
+	if policy == nil || policy.RetentionPeriod == 0 {
+		err = fmt.Errorf("Bucket gs://%v does not have a retention policy configured", r.b.Name)
+	} else if !policy.IsLocked {
+		err = fmt.Errorf("Bucket gs://%v has a retention policy that is not locked", r.b.Name)
+	}
+	return
+}
@@ -144,3 +144,84 @@ func TestStorageBucketResourceHasBucketPolicyOnlyEnabled(t *testing.T) {
 	// TODO - add a bucket with the BucketPolicyOnly IAM configuration and test it
 
 }
+
+func TestStorageBucketResourceIsVersioningEnabled(t *testing.T) {
+
+	// Assert that the bucket does not have versioning enabled
+	bucketResource := NewStorageBucketResource(testValidBucket)
+	assert.False(t, bucketResource.IsVersioningEnabled())
+
+	// Assert that a bucket with versioning is detected
+	bucketResource = NewStorageBucketResource(&storage.Bucket{
+		Name:       "my-versioned-bucket",
+		Versioning: &storage.BucketVersioning{Enabled: true},
+	})
+	assert.True(t, bucketResource.IsVersioningEnabled())
+}
+
+func TestStorageBucketResourceHasRetentionPolicy(t *testing.T) {
+
+	// Assert that the bucket does not have a retention policy
+	bucketResource := NewStorageBucketResource(testValidBucket)
+	assert.NotNil(t, bucketResource.HasRetentionPolicy())
+
+	// Assert that an unlocked retention policy is reported
+	bucketResource = NewStorageBucketResource(&storage.Bucket{
+		Name:            "my-retained-bucket",
+		RetentionPolicy: &storage.BucketRetentionPolicy{RetentionPeriod: 86400},
+	})
+	assert.NotNil(t, bucketResource.HasRetentionPolicy())
+
+	// Assert that a locked retention policy passes
+	bucketResource = NewStorageBucketResource(&storage.Bucket{
+		Name:            "my-locked-bucket",
+		RetentionPolicy: &storage.BucketRetentionPolicy{RetentionPeriod: 86400, IsLocked: true},
+	})
+	assert.Nil(t, bucketResource.HasRetentionPolicy())
+}
+
+func TestStorageBucketResourcePolicyAllowsMember(t *testing.T) {
+
+	// Assert that the bucket without a policy does not allow `allUsers`
+	bucketResource := NewStorageBucketResource(testValidBucket)
+	assert.False(t, bucketResource.PolicyAllowsMember("allUsers"))
+
+	// Assert that `allUsers` granted through IAM is detected, even without ACLs
+	bucketResource.Policy = NewIamPolicyResourceFromStorage(&storage.Policy{
+		Bindings: []*storage.PolicyBindings{
+			{Role: "roles/storage.objectViewer", Members: []string{"allUsers"}},
+		},
+	})
+	assert.True(t, bucketResource.PolicyAllowsMember("allUsers"))
+	assert.False(t, bucketResource.PolicyAllowsMember("allAuthenticatedUsers"))
+	assert.False(t, bucketResource.AllowAllUsers())
+}
+
+func TestStorageBucketResourceIsLoggingEnabled(t *testing.T) {
+
+	// Assert that the bucket does not have access logging
+	bucketResource := NewStorageBucketResource(testValidBucket)
+	assert.False(t, bucketResource.IsLoggingEnabled())
+
+	bucketResource = NewStorageBucketResource(&storage.Bucket{
+		Name:    "my-logged-bucket",
+		Logging: &storage.BucketLogging{LogBucket: "my-log-bucket"},
+	})
+	assert.True(t, bucketResource.IsLoggingEnabled())
+}
+
+func TestStorageBucketResourceHasPublicObjects(t *testing.T) {
+
+	// Assert that the bucket has no public objects
+	bucketResource := NewStorageBucketResource(testValidBucket)
+	assert.Nil(t, bucketResource.HasPublicObjects())
+
+	// Assert that a sampled public object is detected
+	bucketResource.SampledObjects = []*storage.Object{
+		{
+			Name: "index.html",
+			Acl:  []*storage.ObjectAccessControl{{Entity: "allUsers", Role: "READER"}},
+		},
+	}
+	assert.NotNil(t, bucketResource.HasPublicObjects())
+}
@@ -2,90 +2,101 @@
 package runner
 
 import (
-	"flag"
-
 	"github.com/UnityTech/nemesis/pkg/client"
+	"github.com/UnityTech/nemesis/pkg/config"
 	"github.com/UnityTech/nemesis/pkg/report"
 	"github.com/UnityTech/nemesis/pkg/utils"
 	"github.com/golang/glog"
 )
 
-var (
-	flagReportEnableStdout  = flag.Bool("reports.stdout.enable", utils.GetEnvBool("NEMESIS_ENABLE_STDOUT"), "Enable outputting report via stdout")
-	flagReportEnablePubsub  = flag.Bool("reports.pubsub.enable", utils.GetEnvBool("NEMESIS_ENABLE_PUBSUB"), "Enable outputting report via Google Pub/Sub")
-	flagReportPubsubProject = flag.String("reports.pubsub.project", utils.GetEnv("NEMESIS_PUBSUB_PROJECT", ""), "Indicate which GCP project to output Pub/Sub reports to")
-	flagReportPubsubTopic   = flag.String("reports.pubsub.topic", utils.GetEnv("NEMESIS_PUBSUB_TOPIC", "nemesis"), "Indicate which topic to output Pub/Sub reports to")
-)
-
 // Audit is a runner that encapsulates the logic of an audit against GCP resources
 type Audit struct {
-	c         *client.Client
-	reports   []report.Report
+	cfg     *config.Config
+	c       *client.Client
+	reports []report.Report
+
+	// Reporters that stream reports, which only receive failed controls when only failures are requested
 	reporters []report.Reporter
+
+	// Reporters that write files, which compute scores and summaries from every control
+	fileReporters []report.Reporter
 }
 
 // NewAudit returns a new Audit runner
-func NewAudit() *Audit {
+func NewAudit(cfg *config.Config) *Audit {
+	utils.SetDebug(cfg.Debug)
+
 	a := new(Audit)
+	a.cfg = cfg
 	a.reports = []report.Report{}
 	a.reporters = []report.Reporter{}
+	a.fileReporters = []report.Reporter{}
 	return a
 }
 
 // Setup configures an Audit runner and sets up audit resources
 func (a *Audit) Setup() {
-	a.c = client.New()
+	a.c = client.New(a.cfg)
 
 	a.setupReporters()
 
+	// Nothing can be audited without the list of projects
 	if err := a.c.GetProjects(); err != nil {
 		glog.Fatalf("Failed to retrieve project resources: %v", err)
 	}
 
-	if err := a.c.GetIamResources(); err != nil {
-		glog.Fatalf("Failed to retrieve iam resources: %v", err)
+	// Collectors capture failures per project and resource type, so a failing collector
+	// only results in partial results rather than aborting the audit
+	collectors := []func() error{
+		a.c.GetIamResources,
+		a.c.GetComputeResources,
+		a.c.GetLoggingResources,
+		a.c.GetNetworkResources,
+		a.c.GetContainerResources,
+		a.c.GetStorageResources,
+		a.c.GetSQLResources,
+		a.c.GetDNSResources,
+		a.c.GetKMSResources,
+		a.c.GetAPIKeyResources,
+		a.c.GetOrgPolicyResources,
 	}
 
-	if err := a.c.GetComputeResources(); err != nil {
-		glog.Fatalf("Failed to retrieve compute resources: %v", err)
+	for _, f := range collectors {
+		if err := f(); err != nil {
+			glog.Errorf("Failed to retrieve resources: %v", err)
+		}
 	}
+}
 
-	if err := a.c.GetLoggingResources(); err != nil {
-		glog.Fatalf("Failed to retrieve logging resources: %v", err)
+func (a *Audit) setupReporters() {
+	// If pubsub client is required, create it here. The project and topic were validated with the configuration
+	if a.cfg.Reports.PubSub.Enable {
+		a.reporters = append(a.reporters, report.NewPubSubReporter(a.cfg.Reports.PubSub.Project, a.cfg.Reports.PubSub.Topic))
 	}
 
-	if err := a.c.GetNetworkResources(); err != nil {
-		glog.Fatalf("Failed to retrieve network resources: %v", err)
+	// Setup stdout
+	if a.cfg.Reports.Stdout.Enable {
+		a.reporters = append(a.reporters, report.NewStdOutReporter(a.cfg.Reports.Stdout.Format))
 	}
 
-	if err := a.c.GetContainerResources(); err != nil {
-		glog.Fatalf("Failed to retrieve container resources: %v", err)
+	// Setup the SARIF log file
+	if a.cfg.Reports.Sarif.Path != "" {
+		a.fileReporters = append(a.fileReporters, report.NewSarifReporter(a.cfg.Reports.Sarif.Path))
 	}
 
-	if err := a.c.GetStorageResources(); err != nil {
-		glog.Fatalf("Failed to retrieve storage resources: %v", err)
+	// Setup the JUnit XML file
+	if a.cfg.Reports.JUnit.Path != "" {
+		a.fileReporters = append(a.fileReporters, report.NewJUnitReporter(a.cfg.Reports.JUnit.Path))
 	}
-}
-
-func (a *Audit) setupReporters() {
-	// If pubsub client is required, create it here
-	if *flagReportEnablePubsub {
-
-		if *flagReportPubsubProject == "" {
-			glog.Fatal("PubSub project not specified")
 
-		}
-		if *flagReportPubsubTopic == "" {
-			glog.Fatal("PubSub topic not specified")
-		}
-
-		// Create the pubsub client
-		a.reporters = append(a.reporters, report.NewPubSubReporter(*flagReportPubsubProject, *flagReportPubsubTopic))
+	// Setup the HTML file
+	if a.cfg.Reports.HTML.Path != "" {
+		a.fileReporters = append(a.fileReporters, report.NewHTMLReporter(a.cfg.Reports.HTML.Path))
 	}
 
-	// Setup stdout
-	if *flagReportEnableStdout {
-		a.reporters = append(a.reporters, report.NewStdOutReporter())
+	// Setup the CSV file
+	if a.cfg.Reports.CSV.Path != "" {
+		a.fileReporters = append(a.fileReporters, report.NewCSVReporter(a.cfg.Reports.CSV.Path, a.cfg.Reports.CSV.Columns))
 	}
 }
 
@@ -102,10 +113,17 @@ func (a *Audit) Execute() {
 		a.c.GenerateComputeSubnetworkReports,
 		a.c.GenerateComputeFirewallRuleReports,
 		a.c.GenerateComputeAddressReports,
+		a.c.GenerateInstanceExposureReports,
 		a.c.GenerateIAMPolicyReports,
 		a.c.GenerateStorageBucketReports,
 		a.c.GenerateContainerClusterReports,
 		a.c.GenerateContainerNodePoolReports,
+		a.c.GenerateSQLInstanceReports,
+		a.c.GenerateDNSManagedZoneReports,
+		a.c.GenerateKMSCryptoKeyReports,
+		a.c.GenerateAPIKeyReports,
+		a.c.GenerateOrgPolicyReports,
+		a.c.GenerateCollectionErrorReports,
 	}
 
 	for _, f := range generators {
@@ -126,11 +144,30 @@ func (a *Audit) Report() {
 		glog.Fatalf("Failed to push metrics: %v", err)
 	}
 
+	// Controls that did not fail are left out of the streamed reports when only failures are requested
+	reports := a.reports
+	if a.cfg.Reports.OnlyFailures {
+		reports = report.OnlyFailures(reports)
+	}
+
 	// Push outputs
 	for _, r := range a.reporters {
+		err := r.Publish(reports)
+		if err != nil {
+			glog.Fatalf("Failed to publish reports: %v", err)
+		}
+	}
+
+	// Write files with every control, so pass rates and test results are complete
+	for _, r := range a.fileReporters {
 		err := r.Publish(a.reports)
 		if err != nil {
 			glog.Fatalf("Failed to publish reports: %v", err)
 		}
 	}
 }
+
+// CheckThreshold returns an error when the audit found more failed controls than the configured threshold tolerates
+func (a *Audit) CheckThreshold() error {
+	return a.cfg.Threshold.Check(a.reports)
+}
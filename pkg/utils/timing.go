@@ -5,9 +5,16 @@ import (
 	"time"
 )
 
+var debug bool
+
+// SetDebug enables or disables verbose output for debugging
+func SetDebug(enabled bool) {
+	debug = enabled
+}
+
 // Elapsed sets up a goroutine to indicate how long it took to run a segment of code
 func Elapsed(what string) func() {
-	if *flagDebug {
+	if debug {
 		start := time.Now()
 		return func() {
 			fmt.Printf("%s took %v\n", what, time.Since(start))